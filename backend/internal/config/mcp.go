@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
+
+// MCPConfig MCP 配置（对应 config.yaml 的 mcp 段）
+type MCPConfig struct {
+	Enabled     bool               `mapstructure:"enabled"`
+	ToolsPath   string             `mapstructure:"tools_path"`
+	ExecutePath string             `mapstructure:"execute_path"`
+	PromptsDir  string             `mapstructure:"prompts_dir"`
+	Auth        MCPAuthConfig      `mapstructure:"auth"`
+	Audit       MCPAuditConfig     `mapstructure:"audit"`
+	Tools       MCPToolsConfig     `mapstructure:"tools"`
+	OpenAPI     MCPOpenAPIConfig   `mapstructure:"openapi"`
+	Remotes     []MCPRemoteConfig  `mapstructure:"remotes"`
+	RateLimit   MCPRateLimitConfig `mapstructure:"rate_limit"`
+	Batch       MCPBatchConfig     `mapstructure:"batch"`
+	Session     MCPSessionConfig   `mapstructure:"session"`
+}
+
+// MCPSessionConfig Streamable HTTP 会话限制，为 0 时使用默认值
+type MCPSessionConfig struct {
+	MaxSessions int `mapstructure:"max_sessions"`
+	IdleTimeout int `mapstructure:"idle_timeout"` // 空闲超时（秒）
+}
+
+// MCPBatchConfig 批量执行限制，为 0 时使用默认值
+type MCPBatchConfig struct {
+	MaxSize        int `mapstructure:"max_size"`
+	MaxConcurrency int `mapstructure:"max_concurrency"`
+}
+
+// MCPRateLimitConfig MCP 工具调用限流配置（令牌桶）
+type MCPRateLimitConfig struct {
+	Enabled     bool            `mapstructure:"enabled"`
+	Store       string          `mapstructure:"store"`
+	RedisPrefix string          `mapstructure:"redis_prefix"`
+	Global      MCPLimitConfig  `mapstructure:"global"`
+	PerTool     MCPLimitConfig  `mapstructure:"per_tool"`
+	PerClient   MCPLimitConfig  `mapstructure:"per_client"`
+	Tools       []MCPNamedLimit `mapstructure:"tools"`
+	Clients     []MCPNamedLimit `mapstructure:"clients"`
+}
+
+// MCPLimitConfig 每分钟调用次数与突发容量，per_minute 为 0 表示不限制
+type MCPLimitConfig struct {
+	PerMinute int `mapstructure:"per_minute"`
+	Burst     int `mapstructure:"burst"`
+}
+
+// MCPNamedLimit 单个工具或调用方的限制（name 为工具名或 API Key 名称 / Token subject）
+type MCPNamedLimit struct {
+	Name      string `mapstructure:"name"`
+	PerMinute int    `mapstructure:"per_minute"`
+	Burst     int    `mapstructure:"burst"`
+}
+
+// MCPRemoteConfig 远程 MCP 服务（联邦模式：以 name 为前缀重新导出其工具）
+type MCPRemoteConfig struct {
+	Name      string            `mapstructure:"name"`
+	Transport string            `mapstructure:"transport"`
+	URL       string            `mapstructure:"url"`
+	Headers   map[string]string `mapstructure:"headers"`
+	Command   string            `mapstructure:"command"`
+	Args      []string          `mapstructure:"args"`
+	Env       []string          `mapstructure:"env"`
+	Include   []string          `mapstructure:"include"`
+	Exclude   []string          `mapstructure:"exclude"`
+	Scopes    []string          `mapstructure:"scopes"`
+}
+
+// MCPOpenAPIConfig 将 REST 接口（swag 生成的文档）自动暴露为 MCP 工具
+type MCPOpenAPIConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	SpecPath string   `mapstructure:"spec_path"`
+	Include  []string `mapstructure:"include"`
+	Exclude  []string `mapstructure:"exclude"`
+	Scopes   []string `mapstructure:"scopes"`
+}
+
+// MCPToolsConfig MCP 工具启用策略
+type MCPToolsConfig struct {
+	Allow     []string          `mapstructure:"allow"`
+	Deny      []string          `mapstructure:"deny"`
+	Overrides []MCPToolOverride `mapstructure:"overrides"`
+}
+
+// MCPToolOverride 单个工具的配置覆盖（使用列表而非 map，避免 viper 将工具名转为小写）
+type MCPToolOverride struct {
+	Name        string `mapstructure:"name"`
+	Description string `mapstructure:"description"`
+	Timeout     int    `mapstructure:"timeout"`
+}
+
+// MCPAuthConfig MCP 认证配置
+type MCPAuthConfig struct {
+	Enabled              bool           `mapstructure:"enabled"`
+	Resource             string         `mapstructure:"resource"`
+	AuthorizationServers []string       `mapstructure:"authorization_servers"`
+	APIKeys              []APIKeyConfig `mapstructure:"api_keys"`
+	JWT                  JWTConfig      `mapstructure:"jwt"`
+}
+
+// APIKeyConfig API Key 配置
+type APIKeyConfig struct {
+	Name   string   `mapstructure:"name"`
+	Key    string   `mapstructure:"key"`
+	Scopes []string `mapstructure:"scopes"`
+}
+
+// JWTConfig Bearer Token（JWT）校验配置
+type JWTConfig struct {
+	Secret        string `mapstructure:"secret"`
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	Issuer        string `mapstructure:"issuer"`
+	Audience      string `mapstructure:"audience"`
+}
+
+// MCPAuditConfig MCP 审计日志配置
+type MCPAuditConfig struct {
+	Enabled       bool   `mapstructure:"enabled"`
+	Sink          string `mapstructure:"sink"`
+	FilePath      string `mapstructure:"file_path"`
+	RetentionDays int    `mapstructure:"retention_days"`
+}
+
+// GetMCP 读取 MCP 配置
+func GetMCP() (*MCPConfig, error) {
+	var cfg MCPConfig
+	if err := viper.UnmarshalKey("mcp", &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse mcp config: %w", err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/viper"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// OpenMySQL 根据 database.mysql 配置打开 MySQL 连接
+func OpenMySQL() (*gorm.DB, error) {
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local&timeout=%ds",
+		viper.GetString("database.mysql.username"),
+		viper.GetString("database.mysql.password"),
+		viper.GetString("database.mysql.host"),
+		viper.GetInt("database.mysql.port"),
+		viper.GetString("database.mysql.database"),
+		viper.GetString("database.mysql.charset"),
+		viper.GetInt("database.mysql.connect_timeout"),
+	)
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(viper.GetInt("database.mysql.pool_size"))
+	sqlDB.SetMaxIdleConns(viper.GetInt("database.mysql.max_idle"))
+	sqlDB.SetConnMaxLifetime(time.Duration(viper.GetInt("database.mysql.max_lifetime")) * time.Second)
+
+	return db, nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/viper"
+)
+
+// redisOptions 根据 database.redis 配置生成连接池选项
+func redisOptions() *redis.Options {
+	host := viper.GetString("database.redis.host")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := viper.GetInt("database.redis.port")
+	if port == 0 {
+		port = 6379
+	}
+	timeout := time.Duration(viper.GetInt("database.redis.connect_timeout")) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	return &redis.Options{
+		Addr:         net.JoinHostPort(host, strconv.Itoa(port)),
+		Password:     viper.GetString("database.redis.password"),
+		DB:           viper.GetInt("database.redis.db"),
+		PoolSize:     viper.GetInt("database.redis.pool_size"),
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	}
+}
+
+// OpenRedis 根据 database.redis 配置创建 Redis 客户端（连接池）并校验连接
+func OpenRedis() (*redis.Client, error) {
+	client := redis.NewClient(redisOptions())
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect redis: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/richer/ai_skeleton/internal/testutil"
+	"github.com/spf13/viper"
+)
+
+// setRedisConfig 将 database.redis 指向 addr，测试结束时恢复配置
+func setRedisConfig(t *testing.T, addr, password string) {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	testutil.AssertNoError(t, err)
+	p, _ := strconv.Atoi(port)
+
+	viper.Set("database.redis.host", host)
+	viper.Set("database.redis.port", p)
+	viper.Set("database.redis.password", password)
+	viper.Set("database.redis.connect_timeout", 1)
+	t.Cleanup(viper.Reset)
+}
+
+func TestOpenRedis(t *testing.T) {
+	srv := miniredis.RunT(t)
+	srv.RequireAuth("secret")
+
+	t.Run("连接成功", func(t *testing.T) {
+		setRedisConfig(t, srv.Addr(), "secret")
+		client, err := OpenRedis()
+		testutil.AssertNoError(t, err)
+		defer client.Close()
+
+		ctx := context.Background()
+		testutil.AssertNoError(t, client.Set(ctx, "k", "v", 0).Err())
+		v, err := client.Get(ctx, "k").Result()
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, v, "v")
+	})
+
+	t.Run("认证失败", func(t *testing.T) {
+		setRedisConfig(t, srv.Addr(), "wrong")
+		_, err := OpenRedis()
+		testutil.AssertError(t, err)
+	})
+}
+
+func TestRedisOptions_Defaults(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	opts := redisOptions()
+	testutil.AssertEqual(t, opts.Addr, "127.0.0.1:6379")
+	testutil.AssertEqual(t, opts.DialTimeout.Seconds(), float64(5))
+}
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/richer/ai_skeleton/internal/common"
@@ -10,11 +13,18 @@ import (
 
 var mcpAdapter mcp.MCPAdapter
 
+var mcpBatchLimits mcp.BatchLimits
+
 // InitMCP 初始化 MCP 适配器（在 router setup 时调用一次）
 func InitMCP(adapter mcp.MCPAdapter) {
 	mcpAdapter = adapter
 }
 
+// InitMCPBatch 设置批量执行的限制（在 router setup 时调用一次）
+func InitMCPBatch(limits mcp.BatchLimits) {
+	mcpBatchLimits = limits
+}
+
 // MCPListTools 列出所有 MCP 工具
 // @Summary 列出 MCP 工具
 // @Description 列出所有已注册的 MCP 工具
@@ -36,6 +46,7 @@ func MCPListTools(c *gin.Context) {
 // @Produce json
 // @Param request body mcp.MCPRequest true "MCP 请求"
 // @Success 200 {object} common.Response{data=mcp.MCPResponse}
+// @Failure 429 {object} common.Response "调用被限流，Retry-After 头给出重试秒数"
 // @Router /api/v1/mcp/execute [post]
 func MCPExecute(c *gin.Context) {
 	var req mcp.MCPRequest
@@ -44,11 +55,188 @@ func MCPExecute(c *gin.Context) {
 		return
 	}
 
+	if !authorizeMCPTool(c, req.Tool) {
+		return
+	}
+
 	result, err := mcpAdapter.HandleRequest(c.Request.Context(), &req)
 	if err != nil {
+		var limitErr *mcp.RateLimitError
+		if errors.As(err, &limitErr) {
+			c.Header("Retry-After", strconv.Itoa(limitErr.RetryAfterSeconds()))
+			c.JSON(http.StatusTooManyRequests, common.Error(429, err.Error()))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, common.Error(500, err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, common.Success(result))
 }
+
+// MCPExecuteBatch 批量执行 MCP 工具
+// @Summary 批量执行 MCP 工具
+// @Description 使用有界 worker 池并发执行多个 MCP 工具，结果顺序与请求一致；stop_on_error 为 true 时任一调用失败后跳过其余调用；调用数超过 mcp.batch.max_size 时返回 400，并发数不超过 mcp.batch.max_concurrency
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Param request body mcp.MCPBatchRequest true "批量请求"
+// @Success 200 {object} common.Response{data=mcp.MCPBatchResponse}
+// @Router /api/v1/mcp/execute/batch [post]
+func MCPExecuteBatch(c *gin.Context) {
+	var req mcp.MCPBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: "+err.Error()))
+		return
+	}
+
+	// 任一工具权限不足时整批拒绝
+	for _, r := range req.Requests {
+		if !authorizeMCPTool(c, r.Tool) {
+			return
+		}
+	}
+
+	resp, err := mcp.ExecuteBatch(c.Request.Context(), mcpAdapter, &req, mcpBatchLimits)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: "+err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, common.Success(resp))
+}
+
+// MCPListResources 列出所有 MCP 资源
+// @Summary 列出 MCP 资源
+// @Description 列出所有已注册的 MCP 资源与资源模板
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=mcp.ResourceList}
+// @Router /api/v1/mcp/resources [get]
+func MCPListResources(c *gin.Context) {
+	c.JSON(http.StatusOK, common.Success(mcp.ResourceList{
+		Resources:         mcpAdapter.ListResources(),
+		ResourceTemplates: mcpAdapter.ListResourceTemplates(),
+	}))
+}
+
+// MCPReadResource 读取 MCP 资源
+// @Summary 读取 MCP 资源
+// @Description 按 URI 读取 MCP 资源内容，支持资源模板匹配
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Param uri query string true "资源 URI"
+// @Success 200 {object} common.Response{data=[]mcp.ResourceContents}
+// @Router /api/v1/mcp/resources/read [get]
+func MCPReadResource(c *gin.Context) {
+	uri := c.Query("uri")
+	if uri == "" {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: uri is required"))
+		return
+	}
+	if !authorizeMCPResource(c, uri) {
+		return
+	}
+
+	contents, err := mcpAdapter.ReadResource(c.Request.Context(), uri)
+	if err != nil {
+		if errors.Is(err, mcp.ErrResourceNotFound) {
+			c.JSON(http.StatusNotFound, common.Error(404, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, common.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(contents))
+}
+
+// MCPSubscribeResource 订阅 MCP 资源变更
+// @Summary 订阅 MCP 资源变更
+// @Description 以 Server-Sent Events 推送指定资源的 notifications/resources/updated 通知
+// @Tags MCP
+// @Produce text/event-stream
+// @Param uri query string true "资源 URI"
+// @Success 200 {object} mcp.Notification
+// @Router /api/v1/mcp/resources/subscribe [get]
+func MCPSubscribeResource(c *gin.Context) {
+	uri := c.Query("uri")
+	if uri == "" {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: uri is required"))
+		return
+	}
+	if !authorizeMCPResource(c, uri) {
+		return
+	}
+
+	events := make(chan mcp.Notification, 16)
+	unsubscribe := mcpAdapter.Subscribe(func(n mcp.Notification) {
+		if n.Method != mcp.MethodResourcesUpdated || n.Params["uri"] != uri {
+			return
+		}
+		// 客户端消费过慢时丢弃通知，避免阻塞发布方
+		select {
+		case events <- n:
+		default:
+		}
+	})
+	defer unsubscribe()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case n := <-events:
+			c.SSEvent("message", n)
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// MCPListPrompts 列出所有 MCP 提示词
+// @Summary 列出 MCP 提示词
+// @Description 列出所有已注册的 MCP 提示词模板
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=[]mcp.Prompt}
+// @Router /api/v1/mcp/prompts [get]
+func MCPListPrompts(c *gin.Context) {
+	c.JSON(http.StatusOK, common.Success(mcpAdapter.ListPrompts()))
+}
+
+// MCPGetPrompt 渲染 MCP 提示词
+// @Summary 渲染 MCP 提示词
+// @Description 使用参数渲染指定的 MCP 提示词模板
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Param request body mcp.PromptRequest true "提示词请求"
+// @Success 200 {object} common.Response{data=mcp.PromptResult}
+// @Router /api/v1/mcp/prompts/get [post]
+func MCPGetPrompt(c *gin.Context) {
+	var req mcp.PromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: "+err.Error()))
+		return
+	}
+	if !authorizeMCPPrompt(c, req.Name) {
+		return
+	}
+
+	result, err := mcpAdapter.GetPrompt(c.Request.Context(), req.Name, req.Arguments)
+	if err != nil {
+		switch {
+		case errors.Is(err, mcp.ErrPromptNotFound):
+			c.JSON(http.StatusNotFound, common.Error(404, err.Error()))
+		case errors.Is(err, mcp.ErrInvalidPromptArgs):
+			c.JSON(http.StatusBadRequest, common.Error(400, err.Error()))
+		default:
+			c.JSON(http.StatusInternalServerError, common.Error(500, err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(result))
+}
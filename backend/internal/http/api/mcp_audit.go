@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/http/middleware"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+var mcpAuditSink mcp.AuditSink
+
+// InitMCPAudit 初始化审计存储（在 router setup 时调用一次）
+func InitMCPAudit(sink mcp.AuditSink) {
+	mcpAuditSink = sink
+}
+
+// MCPListAudit 查询 MCP 工具调用审计记录
+// @Summary 查询 MCP 审计记录
+// @Description 按条件查询最近的工具调用审计记录（按时间倒序）；启用认证时需要 mcp:audit 权限
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Param tool query string false "工具名称"
+// @Param subject query string false "调用方"
+// @Param success query bool false "是否成功"
+// @Param since query string false "起始时间（RFC3339）"
+// @Param limit query int false "返回条数，默认 100"
+// @Success 200 {object} common.Response{data=[]mcp.AuditRecord}
+// @Router /api/v1/mcp/audit [get]
+func MCPListAudit(c *gin.Context) {
+	if mcpAuditSink == nil {
+		c.JSON(http.StatusNotFound, common.Error(404, "mcp audit is disabled"))
+		return
+	}
+
+	if mcpAuthMetadata != nil {
+		p, ok := mcp.PrincipalFromContext(c.Request.Context())
+		if !ok || !p.HasScopes(mcp.AuditScope) {
+			middleware.InsufficientScope(c, &mcp.ScopeError{Tool: "audit", Required: []string{mcp.AuditScope}})
+			return
+		}
+	}
+
+	q := mcp.AuditQuery{
+		Tool:    c.Query("tool"),
+		Subject: c.Query("subject"),
+	}
+	if v := c.Query("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.Error(400, "invalid success: "+v))
+			return
+		}
+		q.Success = &success
+	}
+	if v := c.Query("since"); v != "" {
+		since, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.Error(400, "invalid since: "+v))
+			return
+		}
+		q.Since = since
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, common.Error(400, "invalid limit: "+v))
+			return
+		}
+		q.Limit = limit
+	}
+
+	records, err := mcpAuditSink.Query(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.Error(500, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, common.Success(records))
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/http/middleware"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// mcpAuthMetadata 受保护资源元数据，为 nil 表示未启用认证
+var mcpAuthMetadata *mcp.ProtectedResourceMetadata
+
+// InitMCPAuth 启用 MCP 认证（在 router setup 时调用一次）
+func InitMCPAuth(metadata mcp.ProtectedResourceMetadata) {
+	mcpAuthMetadata = &metadata
+}
+
+// MCPProtectedResourceMetadata OAuth 受保护资源元数据
+// @Summary MCP 受保护资源元数据
+// @Description 返回 RFC 9728 受保护资源元数据，供 Agent 发现授权服务器与可用权限
+// @Tags MCP
+// @Produce json
+// @Success 200 {object} mcp.ProtectedResourceMetadata
+// @Router /.well-known/oauth-protected-resource [get]
+func MCPProtectedResourceMetadata(c *gin.Context) {
+	metadata := *mcpAuthMetadata
+	if metadata.Resource == "" {
+		metadata.Resource = middleware.RequestOrigin(c) + "/api/v1/mcp"
+	}
+	metadata.ScopesSupported = mcp.ScopesSupported(mcpAdapter)
+	metadata.BearerMethodsSupported = []string{"header"}
+
+	c.JSON(http.StatusOK, metadata)
+}
+
+// authorizeMCPTool 校验调用方是否有权调用工具，无权时写入 401/403 响应并返回 false
+func authorizeMCPTool(c *gin.Context, name string) bool {
+	if mcpAuthMetadata == nil {
+		return true
+	}
+
+	schema, ok := mcpAdapter.GetTool(name)
+	if !ok {
+		return true
+	}
+	return checkMCPAuthorization(c, mcp.Authorize(c.Request.Context(), schema))
+}
+
+// authorizeMCPResource 校验调用方是否有权读取或订阅资源，无权时写入 401/403 响应并返回 false
+func authorizeMCPResource(c *gin.Context, uri string) bool {
+	if mcpAuthMetadata == nil {
+		return true
+	}
+	return checkMCPAuthorization(c, mcp.AuthorizeResource(c.Request.Context(), mcpAdapter, uri))
+}
+
+// authorizeMCPPrompt 校验调用方是否有权获取提示词，无权时写入 401/403 响应并返回 false
+func authorizeMCPPrompt(c *gin.Context, name string) bool {
+	if mcpAuthMetadata == nil {
+		return true
+	}
+	return checkMCPAuthorization(c, mcp.AuthorizePrompt(c.Request.Context(), mcpAdapter, name))
+}
+
+// checkMCPAuthorization 将授权错误写为 401/403 响应，授权通过时返回 true
+func checkMCPAuthorization(c *gin.Context, err error) bool {
+	var scopeErr *mcp.ScopeError
+	switch {
+	case err == nil:
+		return true
+	case errors.As(err, &scopeErr):
+		middleware.InsufficientScope(c, scopeErr)
+	default:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, common.Error(401, err.Error()))
+	}
+	return false
+}
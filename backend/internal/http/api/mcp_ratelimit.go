@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/http/middleware"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+var mcpRateLimiter *mcp.RateLimiter
+
+// InitMCPRateLimit 初始化限流器（在 router setup 时调用一次）
+func InitMCPRateLimit(limiter *mcp.RateLimiter) {
+	mcpRateLimiter = limiter
+}
+
+// MCPRateLimitStats 查询 MCP 工具调用限流统计
+// @Summary 查询 MCP 限流统计
+// @Description 返回放行与被限流的调用次数（按维度与工具统计）；启用认证时需要 mcp:audit 权限
+// @Tags MCP
+// @Accept json
+// @Produce json
+// @Success 200 {object} common.Response{data=mcp.RateLimitStats}
+// @Router /api/v1/mcp/ratelimit [get]
+func MCPRateLimitStats(c *gin.Context) {
+	if mcpRateLimiter == nil {
+		c.JSON(http.StatusNotFound, common.Error(404, "mcp rate limit is disabled"))
+		return
+	}
+
+	if mcpAuthMetadata != nil {
+		p, ok := mcp.PrincipalFromContext(c.Request.Context())
+		if !ok || !p.HasScopes(mcp.AuditScope) {
+			middleware.InsufficientScope(c, &mcp.ScopeError{Tool: "ratelimit", Required: []string{mcp.AuditScope}})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, common.Success(mcpRateLimiter.Stats()))
+}
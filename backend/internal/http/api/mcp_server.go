@@ -0,0 +1,288 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// MCPSessionHeader Streamable HTTP 传输的会话头
+const MCPSessionHeader = "Mcp-Session-Id"
+
+var mcpServer *mcp.Server
+
+// InitMCPServer 初始化 MCP JSON-RPC 服务端（在 router setup 时调用一次）
+func InitMCPServer(server *mcp.Server) {
+	mcpServer = server
+}
+
+// MCPStreamPost MCP Streamable HTTP 消息入口
+// @Summary MCP JSON-RPC 消息
+// @Description 接收 MCP JSON-RPC 消息或批量消息（JSON 数组，并发执行）；Accept 包含 text/event-stream 时以 SSE 流式返回进度、日志与最终响应
+// @Tags MCP
+// @Accept json
+// @Produce json,text/event-stream
+// @Param Mcp-Session-Id header string false "会话 ID（initialize 之后必填）"
+// @Param request body mcp.JSONRPCRequest true "JSON-RPC 请求"
+// @Success 200 {object} mcp.JSONRPCResponse
+// @Success 202 "通知已接收"
+// @Router /api/v1/mcp [post]
+func MCPStreamPost(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.Error(400, "invalid request: "+err.Error()))
+		return
+	}
+
+	if isJSONArray(body) {
+		mcpStreamBatch(c, body)
+		return
+	}
+
+	var req mcp.JSONRPCRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		c.JSON(http.StatusBadRequest, mcp.ParseErrorResponse(err))
+		return
+	}
+
+	if !authorizeMCPRequest(c, &req) {
+		return
+	}
+
+	sess, ok := resolveMCPSession(c, &req)
+	if !ok {
+		return
+	}
+
+	// 通知与客户端响应无需返回内容
+	if req.IsNotification() {
+		sess.Handle(c.Request.Context(), &req, nil)
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	if !acceptsEventStream(c) {
+		resp := sess.Handle(c.Request.Context(), &req, nil)
+		if resp == nil {
+			c.Status(http.StatusAccepted)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	// SSE 流式返回：先推送请求过程中的通知，最后推送响应
+	stream := newSSEWriter(c)
+	defer stream.close()
+	if resp := sess.Handle(c.Request.Context(), &req, stream.notify); resp != nil {
+		stream.send(resp)
+	}
+}
+
+// MCPStreamGet 打开会话级 SSE 通知流
+// @Summary MCP 通知流
+// @Description 以 SSE 推送会话级通知（tools/list_changed、resources/updated、日志等）
+// @Tags MCP
+// @Produce text/event-stream
+// @Param Mcp-Session-Id header string true "会话 ID"
+// @Success 200 {object} mcp.JSONRPCNotification
+// @Router /api/v1/mcp [get]
+func MCPStreamGet(c *gin.Context) {
+	sess, ok := mcpServer.Session(c.GetHeader(MCPSessionHeader))
+	if !ok {
+		c.JSON(http.StatusNotFound, common.Error(404, "session not found"))
+		return
+	}
+
+	stream := newSSEWriter(c)
+	defer stream.close()
+	sess.SetNotifier(stream.notify)
+	defer sess.SetNotifier(nil)
+
+	<-c.Request.Context().Done()
+}
+
+// MCPStreamDelete 结束会话
+// @Summary 结束 MCP 会话
+// @Description 关闭会话并取消其所有进行中的请求
+// @Tags MCP
+// @Param Mcp-Session-Id header string true "会话 ID"
+// @Success 204
+// @Router /api/v1/mcp [delete]
+func MCPStreamDelete(c *gin.Context) {
+	id := c.GetHeader(MCPSessionHeader)
+	if _, ok := mcpServer.Session(id); !ok {
+		c.JSON(http.StatusNotFound, common.Error(404, "session not found"))
+		return
+	}
+
+	mcpServer.CloseSession(id)
+	c.Status(http.StatusNoContent)
+}
+
+// mcpStreamBatch 处理 JSON-RPC 批量消息：各消息并发执行，响应按请求顺序以数组返回（SSE 时逐条推送）
+func mcpStreamBatch(c *gin.Context, body []byte) {
+	var reqs []*mcp.JSONRPCRequest
+	if err := json.Unmarshal(body, &reqs); err != nil {
+		c.JSON(http.StatusBadRequest, mcp.ParseErrorResponse(err))
+		return
+	}
+	if len(reqs) == 0 {
+		c.JSON(http.StatusBadRequest, mcp.InvalidRequestResponse("empty batch"))
+		return
+	}
+
+	for _, req := range reqs {
+		if req == nil {
+			continue
+		}
+		if req.Method == "initialize" {
+			c.JSON(http.StatusBadRequest, common.Error(400, "initialize must not be part of a batch"))
+			return
+		}
+		if !authorizeMCPRequest(c, req) {
+			return
+		}
+	}
+
+	id := c.GetHeader(MCPSessionHeader)
+	sess, ok := mcpServer.Session(id)
+	if !ok {
+		if id == "" {
+			c.JSON(http.StatusBadRequest, common.Error(400, "missing "+MCPSessionHeader+" header"))
+			return
+		}
+		c.JSON(http.StatusNotFound, common.Error(404, "session not found"))
+		return
+	}
+
+	if !acceptsEventStream(c) {
+		responses := sess.HandleBatch(c.Request.Context(), reqs, nil)
+		if len(responses) == 0 {
+			c.Status(http.StatusAccepted)
+			return
+		}
+		c.JSON(http.StatusOK, responses)
+		return
+	}
+
+	stream := newSSEWriter(c)
+	defer stream.close()
+	for _, resp := range sess.HandleBatch(c.Request.Context(), reqs, stream.notify) {
+		stream.send(resp)
+	}
+}
+
+// authorizeMCPRequest tools/call、resources/read、resources/subscribe 与 prompts/get 请求按声明的权限校验
+func authorizeMCPRequest(c *gin.Context, req *mcp.JSONRPCRequest) bool {
+	var params struct {
+		Name string `json:"name"`
+		URI  string `json:"uri"`
+	}
+	switch req.Method {
+	case "tools/call":
+		_ = json.Unmarshal(req.Params, &params)
+		return authorizeMCPTool(c, params.Name)
+	case "resources/read", "resources/subscribe":
+		_ = json.Unmarshal(req.Params, &params)
+		return authorizeMCPResource(c, params.URI)
+	case "prompts/get":
+		_ = json.Unmarshal(req.Params, &params)
+		return authorizeMCPPrompt(c, params.Name)
+	}
+	return true
+}
+
+// isJSONArray 请求体是否为 JSON 数组（批量消息）
+func isJSONArray(body []byte) bool {
+	trimmed := bytes.TrimLeft(body, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// resolveMCPSession initialize 请求创建新会话（会话数已达上限时返回 503），其余请求按会话头查找
+func resolveMCPSession(c *gin.Context, req *mcp.JSONRPCRequest) (*mcp.Session, bool) {
+	if req.Method == "initialize" {
+		sess, err := mcpServer.NewSession()
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, common.Error(503, err.Error()))
+			return nil, false
+		}
+		c.Header(MCPSessionHeader, sess.ID)
+		return sess, true
+	}
+
+	id := c.GetHeader(MCPSessionHeader)
+	if id == "" {
+		c.JSON(http.StatusBadRequest, common.Error(400, "missing "+MCPSessionHeader+" header"))
+		return nil, false
+	}
+	sess, ok := mcpServer.Session(id)
+	if !ok {
+		c.JSON(http.StatusNotFound, common.Error(404, "session not found"))
+		return nil, false
+	}
+	return sess, true
+}
+
+// acceptsEventStream 客户端是否接受 SSE 响应
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+// sseWriter 并发安全的 SSE 写入器（工具可能在其他 goroutine 中上报进度）。
+// gin 会在处理函数返回后复用 Context，因此创建时保存响应与请求 context，处理函数返回前调用 close
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	ctx     context.Context
+	closed  bool
+}
+
+// newSSEWriter 写入 SSE 响应头
+func newSSEWriter(c *gin.Context) *sseWriter {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	return &sseWriter{w: c.Writer, flusher: c.Writer, ctx: c.Request.Context()}
+}
+
+// send 写入一条 message 事件，close 之后或客户端断开时丢弃
+func (w *sseWriter) send(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed || w.ctx.Err() != nil {
+		return
+	}
+	fmt.Fprintf(w.w, "event:message\ndata:%s\n\n", data)
+	w.flusher.Flush()
+}
+
+// notify 作为 mcp.NotifyFunc 使用
+func (w *sseWriter) notify(n mcp.JSONRPCNotification) {
+	w.send(n)
+}
+
+// close 停止写入，处理函数返回前调用
+func (w *sseWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// ProtectedResourceMetadataPath OAuth 受保护资源元数据路径（RFC 9728）
+const ProtectedResourceMetadataPath = "/.well-known/oauth-protected-resource"
+
+// InsufficientScope 返回 403 并在 WWW-Authenticate 中给出所需权限
+func InsufficientScope(c *gin.Context, err *mcp.ScopeError) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s", resource_metadata="%s"`,
+		strings.Join(err.Required, " "), ResourceMetadataURL(c)))
+	c.AbortWithStatusJSON(http.StatusForbidden, common.Error(403, err.Error()))
+}
+
+// ResourceMetadataURL 根据请求推导受保护资源元数据地址
+func ResourceMetadataURL(c *gin.Context) string {
+	return RequestOrigin(c) + ProtectedResourceMetadataPath
+}
+
+// RequestOrigin 请求来源地址，兼容反向代理头
+func RequestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
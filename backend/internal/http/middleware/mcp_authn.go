@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/common"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// MCPAuth MCP 认证中间件，认证失败返回 401 并通过 WWW-Authenticate 指明元数据地址
+func MCPAuth(auth *mcp.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := auth.Authenticate(c.Request)
+		if err != nil {
+			challenge := fmt.Sprintf(`Bearer resource_metadata="%s"`, ResourceMetadataURL(c))
+			if errors.Is(err, mcp.ErrInvalidToken) {
+				challenge += fmt.Sprintf(`, error="invalid_token", error_description="%s"`, strings.ReplaceAll(err.Error(), `"`, `'`))
+			}
+			c.Header("WWW-Authenticate", challenge)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, common.Error(401, err.Error()))
+			return
+		}
+
+		c.Request = c.Request.WithContext(mcp.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
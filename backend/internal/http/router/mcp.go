@@ -0,0 +1,274 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/richer/ai_skeleton/internal/config"
+	"github.com/richer/ai_skeleton/internal/database" // ais:feature mysql|redis
+	"github.com/richer/ai_skeleton/internal/http/api"
+	"github.com/richer/ai_skeleton/internal/http/middleware" // ais:feature auth
+	"github.com/richer/ai_skeleton/internal/mcp"
+	"github.com/richer/ai_skeleton/internal/mcp/client"
+	"github.com/spf13/viper"
+)
+
+// setupMCP 初始化 MCP 适配器、注册工具/资源/提示词并挂载 MCP 路由
+func setupMCP(r *gin.Engine, v1 *gin.RouterGroup) {
+	cfg, err := config.GetMCP()
+	if err != nil {
+		log.Fatalf("Failed to load MCP config: %v", err)
+	}
+	if !cfg.Enabled {
+		log.Println("MCP is disabled by config, skipped")
+		return
+	}
+
+	// 初始化 MCP 适配器（按 mcp.tools 策略过滤工具）
+	policy := newMCPToolPolicy(cfg.Tools)
+	mcpAdapter := mcp.WithToolPolicy(mcp.NewMCPAdapter(), policy)
+	if cfg.Audit.Enabled {
+		sink, err := newMCPAuditSink(cfg.Audit)
+		if err != nil {
+			log.Fatalf("Failed to init MCP audit sink: %v", err)
+		}
+		mcpAdapter.Use(mcp.Audit(sink))
+		api.InitMCPAudit(sink)
+	}
+	mcpAdapter.Use(mcp.Recovery(), mcp.Logging(nil))
+	// ais:feature auth
+	if cfg.Auth.Enabled {
+		mcpAdapter.Use(mcp.RequireScopes())
+	}
+	// ais:end
+	if cfg.RateLimit.Enabled {
+		limiter, err := newMCPRateLimiter(cfg.RateLimit)
+		if err != nil {
+			log.Fatalf("Failed to init MCP rate limit: %v", err)
+		}
+		mcpAdapter.Use(limiter.Interceptor())
+		api.InitMCPRateLimit(limiter)
+	}
+	mcpAdapter.Use(mcp.Timeout(time.Duration(viper.GetInt("server.timeout"))*time.Second, policy.Timeouts()))
+
+	// 注册所有工具、资源与提示词
+	if err := mcp.RegisterAllTools(mcpAdapter); err != nil {
+		log.Fatalf("Failed to register MCP tools: %v", err)
+	}
+	if cfg.OpenAPI.Enabled {
+		if err := registerOpenAPITools(mcpAdapter, r, cfg.OpenAPI); err != nil {
+			log.Fatalf("Failed to register MCP tools from OpenAPI spec: %v", err)
+		}
+	}
+	for _, remote := range cfg.Remotes {
+		federateMCPRemote(mcpAdapter, remote)
+	}
+	if err := mcp.RegisterAllResources(mcpAdapter); err != nil {
+		log.Fatalf("Failed to register MCP resources: %v", err)
+	}
+	if err := mcp.RegisterAllPrompts(mcpAdapter); err != nil {
+		log.Fatalf("Failed to register MCP prompts: %v", err)
+	}
+	batchLimits := mcp.BatchLimits{MaxSize: cfg.Batch.MaxSize, MaxConcurrency: cfg.Batch.MaxConcurrency}
+	server := mcp.NewServer(mcpAdapter, mcp.Implementation{
+		Name:    viper.GetString("project.name"),
+		Version: viper.GetString("project.version"),
+	})
+	server.SetBatchLimits(batchLimits)
+	server.SetSessionLimits(mcp.SessionLimits{
+		MaxSessions: cfg.Session.MaxSessions,
+		IdleTimeout: time.Duration(cfg.Session.IdleTimeout) * time.Second,
+	})
+	go server.RunSessionJanitor(context.Background(), time.Minute)
+	api.InitMCP(mcpAdapter)
+	api.InitMCPBatch(batchLimits)
+	api.InitMCPServer(server)
+
+	mcpGroup := v1.Group("/mcp")
+
+	// 认证
+	var authHandlers []gin.HandlerFunc
+	// ais:feature auth
+	if cfg.Auth.Enabled {
+		authenticator, err := newMCPAuthenticator(cfg.Auth)
+		if err != nil {
+			log.Fatalf("Failed to init MCP auth: %v", err)
+		}
+		api.InitMCPAuth(mcp.ProtectedResourceMetadata{
+			Resource:             cfg.Auth.Resource,
+			AuthorizationServers: cfg.Auth.AuthorizationServers,
+		})
+		r.GET(middleware.ProtectedResourceMetadataPath, api.MCPProtectedResourceMetadata)
+		r.GET(middleware.ProtectedResourceMetadataPath+mcpGroup.BasePath(), api.MCPProtectedResourceMetadata)
+		authHandlers = append(authHandlers, middleware.MCPAuth(authenticator))
+		mcpGroup.Use(authHandlers...)
+	}
+	// ais:end
+
+	// 工具列表与执行接口挂载在配置的路径上
+	toolsPath := cfg.ToolsPath
+	if toolsPath == "" {
+		toolsPath = mcpGroup.BasePath() + "/tools"
+	}
+	executePath := cfg.ExecutePath
+	if executePath == "" {
+		executePath = mcpGroup.BasePath() + "/execute"
+	}
+	r.GET(toolsPath, append(authHandlers, api.MCPListTools)...)
+	r.POST(executePath, append(authHandlers, api.MCPExecute)...)
+	r.POST(executePath+"/batch", append(authHandlers, api.MCPExecuteBatch)...)
+
+	{
+		// Streamable HTTP JSON-RPC 传输
+		mcpGroup.POST("", api.MCPStreamPost)
+		mcpGroup.GET("", api.MCPStreamGet)
+		mcpGroup.DELETE("", api.MCPStreamDelete)
+
+		// REST 接口
+		mcpGroup.GET("/resources", api.MCPListResources)
+		mcpGroup.GET("/resources/read", api.MCPReadResource)
+		mcpGroup.GET("/resources/subscribe", api.MCPSubscribeResource)
+		mcpGroup.GET("/prompts", api.MCPListPrompts)
+		mcpGroup.POST("/prompts/get", api.MCPGetPrompt)
+		mcpGroup.GET("/audit", api.MCPListAudit)
+		mcpGroup.GET("/ratelimit", api.MCPRateLimitStats)
+	}
+}
+
+// newMCPToolPolicy 根据配置创建工具启用策略
+func newMCPToolPolicy(cfg config.MCPToolsConfig) mcp.ToolPolicy {
+	policy := mcp.ToolPolicy{
+		Allow:     cfg.Allow,
+		Deny:      cfg.Deny,
+		Overrides: make(map[string]mcp.ToolOverride, len(cfg.Overrides)),
+	}
+	for _, o := range cfg.Overrides {
+		policy.Overrides[o.Name] = mcp.ToolOverride{
+			Description: o.Description,
+			Timeout:     time.Duration(o.Timeout) * time.Second,
+		}
+	}
+	return policy
+}
+
+// registerOpenAPITools 读取 swag 生成的文档，将选中的接口注册为工具并通过 Gin 引擎进程内分发
+func registerOpenAPITools(adapter mcp.MCPAdapter, r *gin.Engine, cfg config.MCPOpenAPIConfig) error {
+	specPath := cfg.SpecPath
+	if specPath == "" {
+		specPath = "./docs/swagger.json"
+	}
+	spec, err := mcp.LoadOpenAPISpec(specPath)
+	if err != nil {
+		return err
+	}
+	return mcp.RegisterOpenAPITools(adapter, spec, r, mcp.OpenAPIToolOptions{
+		Include: cfg.Include,
+		Exclude: cfg.Exclude,
+		// MCP 自身的接口不再桥接
+		ExcludeTags: []string{"MCP"},
+		Scopes:      cfg.Scopes,
+	})
+}
+
+// federateMCPRemote 连接远程 MCP 服务并重新导出其工具；远程服务不可用时只记录日志，不影响启动
+func federateMCPRemote(adapter mcp.MCPAdapter, cfg config.MCPRemoteConfig) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var transport client.Transport
+	switch cfg.Transport {
+	case "", "http":
+		transport = client.NewHTTPTransport(cfg.URL, cfg.Headers, nil)
+	case "stdio":
+		t, err := client.NewStdioTransport(cfg.Command, cfg.Args, cfg.Env)
+		if err != nil {
+			log.Printf("Failed to start remote MCP server %s: %v", cfg.Name, err)
+			return
+		}
+		transport = t
+	default:
+		log.Printf("Unknown transport %q for remote MCP server %s", cfg.Transport, cfg.Name)
+		return
+	}
+
+	c, err := client.Connect(ctx, transport, mcp.Implementation{
+		Name:    viper.GetString("project.name"),
+		Version: viper.GetString("project.version"),
+	})
+	if err != nil {
+		log.Printf("Failed to connect remote MCP server %s: %v", cfg.Name, err)
+		return
+	}
+
+	n, err := client.Federate(ctx, adapter, c, client.FederationOptions{
+		Prefix:  cfg.Name,
+		Include: cfg.Include,
+		Exclude: cfg.Exclude,
+		Scopes:  cfg.Scopes,
+	})
+	if err != nil {
+		log.Printf("Failed to list tools of remote MCP server %s: %v", cfg.Name, err)
+		c.Close()
+		return
+	}
+	log.Printf("Federated %d tools from remote MCP server %s (%s)", n, cfg.Name, c.ServerInfo().Name)
+}
+
+// newMCPRateLimiter 根据配置创建限流器
+func newMCPRateLimiter(cfg config.MCPRateLimitConfig) (*mcp.RateLimiter, error) {
+	opts := mcp.RateLimitOptions{
+		Global:    mcp.PerMinute(cfg.Global.PerMinute, cfg.Global.Burst),
+		PerTool:   mcp.PerMinute(cfg.PerTool.PerMinute, cfg.PerTool.Burst),
+		PerClient: mcp.PerMinute(cfg.PerClient.PerMinute, cfg.PerClient.Burst),
+		Tools:     make(map[string]mcp.Limit, len(cfg.Tools)),
+		Clients:   make(map[string]mcp.Limit, len(cfg.Clients)),
+	}
+	for _, t := range cfg.Tools {
+		opts.Tools[t.Name] = mcp.PerMinute(t.PerMinute, t.Burst)
+	}
+	for _, c := range cfg.Clients {
+		opts.Clients[c.Name] = mcp.PerMinute(c.PerMinute, c.Burst)
+	}
+
+	switch cfg.Store {
+	case "", "memory":
+	// ais:feature redis
+	case "redis":
+		client, err := database.OpenRedis()
+		if err != nil {
+			return nil, err
+		}
+		opts.Store = mcp.NewRedisRateLimitStore(client, cfg.RedisPrefix)
+	// ais:end
+	default:
+		return nil, fmt.Errorf("unknown mcp.rate_limit.store: %q", cfg.Store)
+	}
+	return mcp.NewRateLimiter(opts), nil
+}
+
+// newMCPAuditSink 根据配置创建审计存储
+func newMCPAuditSink(cfg config.MCPAuditConfig) (mcp.AuditSink, error) {
+	retention := time.Duration(cfg.RetentionDays) * 24 * time.Hour
+
+	switch cfg.Sink {
+	case "", "file":
+		path := cfg.FilePath
+		if path == "" {
+			path = "./logs/mcp_audit.jsonl"
+		}
+		return mcp.NewFileAuditSink(path, retention)
+	// ais:feature mysql
+	case "db":
+		db, err := database.OpenMySQL()
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewDBAuditSink(db, retention)
+	// ais:end
+	default:
+		return nil, fmt.Errorf("unknown mcp.audit.sink: %q", cfg.Sink)
+	}
+}
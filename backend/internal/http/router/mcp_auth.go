@@ -0,0 +1,45 @@
+package router
+
+import (
+	"fmt"
+
+	"github.com/richer/ai_skeleton/internal/config"
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// newMCPAuthenticator 根据配置创建认证器
+func newMCPAuthenticator(cfg config.MCPAuthConfig) (*mcp.Authenticator, error) {
+	opts := mcp.AuthOptions{}
+	for _, k := range cfg.APIKeys {
+		opts.APIKeys = append(opts.APIKeys, mcp.APIKey{Name: k.Name, Key: k.Key, Scopes: k.Scopes})
+	}
+
+	// audience 默认为受保护资源标识（RFC 9728），两者均未配置时拒绝启动
+	audience := cfg.JWT.Audience
+	if audience == "" {
+		audience = cfg.Resource
+	}
+	if (cfg.JWT.PublicKeyFile != "" || cfg.JWT.Secret != "") && audience == "" {
+		return nil, fmt.Errorf("mcp.auth.jwt.audience or mcp.auth.resource is required when jwt is configured")
+	}
+
+	switch {
+	case cfg.JWT.PublicKeyFile != "":
+		verifier, err := mcp.NewRS256Verifier(cfg.JWT.PublicKeyFile, cfg.JWT.Issuer, audience)
+		if err != nil {
+			return nil, err
+		}
+		opts.Verifier = verifier
+	case cfg.JWT.Secret != "":
+		verifier, err := mcp.NewHS256Verifier(cfg.JWT.Secret, cfg.JWT.Issuer, audience)
+		if err != nil {
+			return nil, err
+		}
+		opts.Verifier = verifier
+	}
+
+	if len(opts.APIKeys) == 0 && opts.Verifier == nil {
+		return nil, fmt.Errorf("mcp.auth is enabled but neither api_keys nor jwt is configured")
+	}
+	return mcp.NewAuthenticator(opts), nil
+}
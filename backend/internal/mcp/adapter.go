@@ -3,25 +3,98 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
 )
 
 // MCPAdapter MCP 协议适配器接口
 type MCPAdapter interface {
-	// RegisterTool 注册 MCP 工具
+	// RegisterTool 注册 MCP 工具，同名工具已存在时返回 ErrToolExists
 	RegisterTool(name string, schema ToolSchema, handler ToolHandler) error
 
+	// ReplaceTool 替换已注册的工具，工具不存在时等同于注册
+	ReplaceTool(name string, schema ToolSchema, handler ToolHandler) error
+
+	// UnregisterTool 注销工具，工具不存在时返回 ErrToolNotFound
+	UnregisterTool(name string) error
+
+	// Use 追加工具调用拦截器，先追加的位于调用链外层
+	Use(interceptors ...Interceptor)
+
+	// CallTool 经过拦截器链调用工具，工具不存在时返回 ErrToolNotFound
+	CallTool(ctx context.Context, name string, params map[string]interface{}) (interface{}, error)
+
 	// HandleRequest 处理 MCP 请求
 	HandleRequest(ctx context.Context, req *MCPRequest) (*MCPResponse, error)
 
-	// ListTools 列出所有已注册的工具
+	// GetTool 按名称获取工具描述
+	GetTool(name string) (ToolSchema, bool)
+
+	// ListTools 列出所有已注册的工具（按名称排序）
 	ListTools() []ToolSchema
+
+	// RegisterResource 注册静态资源，同 URI 已存在时返回 ErrResourceExists
+	RegisterResource(resource Resource, handler ResourceHandler) error
+
+	// RegisterResourceTemplate 注册资源模板（如 db://users/{id}）
+	RegisterResourceTemplate(tmpl ResourceTemplate, handler ResourceHandler) error
+
+	// UnregisterResource 按 URI 或 URI 模板注销资源
+	UnregisterResource(uri string) error
+
+	// ListResources 列出所有静态资源（按 URI 排序）
+	ListResources() []Resource
+
+	// ListResourceTemplates 列出所有资源模板（按模板排序）
+	ListResourceTemplates() []ResourceTemplate
+
+	// ReadResource 读取资源，不存在时返回 ErrResourceNotFound
+	ReadResource(ctx context.Context, uri string) ([]ResourceContents, error)
+
+	// NotifyResourceUpdated 通知订阅者资源内容已变更
+	NotifyResourceUpdated(uri string)
+
+	// RegisterPrompt 注册提示词模板，同名提示词已存在时返回 ErrPromptExists
+	RegisterPrompt(prompt Prompt, handler PromptHandler) error
+
+	// UnregisterPrompt 注销提示词模板
+	UnregisterPrompt(name string) error
+
+	// ListPrompts 列出所有提示词模板（按名称排序）
+	ListPrompts() []Prompt
+
+	// GetPrompt 校验参数并渲染提示词，不存在时返回 ErrPromptNotFound
+	GetPrompt(ctx context.Context, name string, args map[string]string) (*PromptResult, error)
+
+	// Subscribe 订阅服务端通知（如 tools/list_changed），返回取消订阅函数
+	Subscribe(handler NotificationHandler) func()
 }
 
 // ToolSchema MCP 工具描述
 type ToolSchema struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Parameters  map[string]interface{} `json:"parameters"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	Parameters   map[string]interface{} `json:"parameters"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"` // 结构化结果的 JSON Schema（type 须为 object），设置后结果携带 structuredContent
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+	Scopes       []string               `json:"scopes,omitempty"` // 调用所需权限，启用认证时校验
+}
+
+// ToolAnnotations 工具行为提示，供客户端展示与确认策略参考（不保证准确，不能作为安全依据）
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+// Hint 返回布尔指针，便于填写 ToolAnnotations
+func Hint(b bool) *bool {
+	return &b
 }
 
 // ToolHandler 工具处理函数
@@ -40,60 +113,277 @@ type MCPResponse struct {
 	Error   string      `json:"error,omitempty"`
 }
 
-// mcpAdapter MCP 适配器实现
+// maxToolNameLen 工具名称的最大长度
+const maxToolNameLen = 128
+
+// toolNamePattern 工具名称规则：字母、数字、下划线、连字符和点，最长 128 个字符
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// mcpAdapter MCP 适配器实现（并发安全）
 type mcpAdapter struct {
+	mu       sync.RWMutex
 	tools    map[string]ToolSchema
 	handlers map[string]ToolHandler
+
+	interceptors []Interceptor
+	resources    *resourceRegistry
+	prompts      *promptRegistry
+	notifier     notifier
 }
 
 // NewMCPAdapter 创建 MCP 适配器
 func NewMCPAdapter() MCPAdapter {
 	return &mcpAdapter{
-		tools:    make(map[string]ToolSchema),
-		handlers: make(map[string]ToolHandler),
+		tools:     make(map[string]ToolSchema),
+		handlers:  make(map[string]ToolHandler),
+		resources: newResourceRegistry(),
+		prompts:   newPromptRegistry(),
 	}
 }
 
 // RegisterTool 注册工具
 func (a *mcpAdapter) RegisterTool(name string, schema ToolSchema, handler ToolHandler) error {
+	schema, err := validateTool(name, schema, handler)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	if _, exists := a.tools[name]; exists {
+		a.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrToolExists, name)
+	}
 	a.tools[name] = schema
 	a.handlers[name] = handler
+	a.mu.Unlock()
+
+	a.notifyToolsChanged()
 	return nil
 }
 
-// HandleRequest 处理请求
-func (a *mcpAdapter) HandleRequest(ctx context.Context, req *MCPRequest) (*MCPResponse, error) {
-	handler, exists := a.handlers[req.Tool]
+// ReplaceTool 替换工具
+func (a *mcpAdapter) ReplaceTool(name string, schema ToolSchema, handler ToolHandler) error {
+	schema, err := validateTool(name, schema, handler)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	a.tools[name] = schema
+	a.handlers[name] = handler
+	a.mu.Unlock()
+
+	a.notifyToolsChanged()
+	return nil
+}
+
+// UnregisterTool 注销工具
+func (a *mcpAdapter) UnregisterTool(name string) error {
+	a.mu.Lock()
+	if _, exists := a.tools[name]; !exists {
+		a.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrToolNotFound, name)
+	}
+	delete(a.tools, name)
+	delete(a.handlers, name)
+	a.mu.Unlock()
+
+	a.notifyToolsChanged()
+	return nil
+}
+
+// Use 追加拦截器
+func (a *mcpAdapter) Use(interceptors ...Interceptor) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.interceptors = append(a.interceptors, interceptors...)
+}
+
+// CallTool 调用工具
+func (a *mcpAdapter) CallTool(ctx context.Context, name string, params map[string]interface{}) (interface{}, error) {
+	a.mu.RLock()
+	schema, exists := a.tools[name]
+	handler := a.handlers[name]
+	interceptors := a.interceptors
+	a.mu.RUnlock()
 	if !exists {
-		return &MCPResponse{
-			Success: false,
-			Error:   "tool not found",
-		}, nil
+		return nil, ErrToolNotFound
+	}
+	if err := validateArguments(schema.Parameters, params); err != nil {
+		return nil, err
+	}
+
+	// 由内向外包装，保证先追加的拦截器位于外层
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		handler = interceptors[i](handler)
 	}
 
-	result, err := handler(ctx, req.Params)
+	ctx = withCallInfo(ctx, CallInfo{Tool: name, Schema: schema})
+	return handler(ctx, params)
+}
+
+// HandleRequest 处理请求
+func (a *mcpAdapter) HandleRequest(ctx context.Context, req *MCPRequest) (*MCPResponse, error) {
+	result, err := a.CallTool(ctx, req.Tool, req.Params)
 	if err != nil {
+		// 限流错误交由传输层处理（如返回 429 与 Retry-After）
+		var limitErr *RateLimitError
+		if errors.As(err, &limitErr) {
+			return nil, err
+		}
 		return &MCPResponse{
 			Success: false,
 			Error:   err.Error(),
 		}, nil
 	}
 
+	// 处理函数返回 CallToolResult 时，REST 接口优先返回结构化结果，否则返回内容块
+	if r, ok := result.(*CallToolResult); ok && r != nil {
+		if r.IsError {
+			return &MCPResponse{Success: false, Error: contentText(r.Content)}, nil
+		}
+		if r.StructuredContent != nil {
+			return &MCPResponse{Success: true, Data: r.StructuredContent}, nil
+		}
+		return &MCPResponse{Success: true, Data: r.Content}, nil
+	}
+
 	return &MCPResponse{
 		Success: true,
 		Data:    result,
 	}, nil
 }
 
+// GetTool 获取工具描述
+func (a *mcpAdapter) GetTool(name string) (ToolSchema, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	schema, ok := a.tools[name]
+	return schema, ok
+}
+
 // ListTools 列出所有工具
 func (a *mcpAdapter) ListTools() []ToolSchema {
+	a.mu.RLock()
 	tools := make([]ToolSchema, 0, len(a.tools))
 	for _, schema := range a.tools {
 		tools = append(tools, schema)
 	}
+	a.mu.RUnlock()
+
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
 	return tools
 }
 
+// RegisterResource 注册静态资源
+func (a *mcpAdapter) RegisterResource(resource Resource, handler ResourceHandler) error {
+	if err := a.resources.register(resource, handler); err != nil {
+		return err
+	}
+	a.notifier.notify(Notification{Method: MethodResourcesListChanged})
+	return nil
+}
+
+// RegisterResourceTemplate 注册资源模板
+func (a *mcpAdapter) RegisterResourceTemplate(tmpl ResourceTemplate, handler ResourceHandler) error {
+	if err := a.resources.registerTemplate(tmpl, handler); err != nil {
+		return err
+	}
+	a.notifier.notify(Notification{Method: MethodResourcesListChanged})
+	return nil
+}
+
+// UnregisterResource 注销资源
+func (a *mcpAdapter) UnregisterResource(uri string) error {
+	if err := a.resources.unregister(uri); err != nil {
+		return err
+	}
+	a.notifier.notify(Notification{Method: MethodResourcesListChanged})
+	return nil
+}
+
+// ListResources 列出静态资源
+func (a *mcpAdapter) ListResources() []Resource {
+	return a.resources.list()
+}
+
+// ListResourceTemplates 列出资源模板
+func (a *mcpAdapter) ListResourceTemplates() []ResourceTemplate {
+	return a.resources.listTemplates()
+}
+
+// ReadResource 读取资源
+func (a *mcpAdapter) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	return a.resources.read(ctx, uri)
+}
+
+// NotifyResourceUpdated 通知资源已变更
+func (a *mcpAdapter) NotifyResourceUpdated(uri string) {
+	a.notifier.notify(Notification{
+		Method: MethodResourcesUpdated,
+		Params: map[string]interface{}{"uri": uri},
+	})
+}
+
+// RegisterPrompt 注册提示词
+func (a *mcpAdapter) RegisterPrompt(prompt Prompt, handler PromptHandler) error {
+	if err := a.prompts.register(prompt, handler); err != nil {
+		return err
+	}
+	a.notifier.notify(Notification{Method: MethodPromptsListChanged})
+	return nil
+}
+
+// UnregisterPrompt 注销提示词
+func (a *mcpAdapter) UnregisterPrompt(name string) error {
+	if err := a.prompts.unregister(name); err != nil {
+		return err
+	}
+	a.notifier.notify(Notification{Method: MethodPromptsListChanged})
+	return nil
+}
+
+// ListPrompts 列出提示词
+func (a *mcpAdapter) ListPrompts() []Prompt {
+	return a.prompts.list()
+}
+
+// GetPrompt 渲染提示词
+func (a *mcpAdapter) GetPrompt(ctx context.Context, name string, args map[string]string) (*PromptResult, error) {
+	return a.prompts.get(ctx, name, args)
+}
+
+// Subscribe 订阅服务端通知
+func (a *mcpAdapter) Subscribe(handler NotificationHandler) func() {
+	return a.notifier.subscribe(handler)
+}
+
+// notifyToolsChanged 通知订阅者工具列表已变更
+func (a *mcpAdapter) notifyToolsChanged() {
+	a.notifier.notify(Notification{Method: MethodToolsListChanged})
+}
+
+// validateTool 校验工具名称与处理函数，并补全 schema 中缺省的名称
+func validateTool(name string, schema ToolSchema, handler ToolHandler) (ToolSchema, error) {
+	if !toolNamePattern.MatchString(name) {
+		return schema, fmt.Errorf("%w: %q", ErrInvalidToolName, name)
+	}
+	if handler == nil {
+		return schema, fmt.Errorf("%w: %s", ErrNilHandler, name)
+	}
+	if schema.Name == "" {
+		schema.Name = name
+	} else if schema.Name != name {
+		return schema, fmt.Errorf("%w: %q != %q", ErrToolNameMismatch, name, schema.Name)
+	}
+	if schema.OutputSchema != nil && schema.OutputSchema["type"] != "object" {
+		return schema, fmt.Errorf("%w: %s", ErrInvalidOutputSchema, name)
+	}
+	return schema, nil
+}
+
 // ToJSONSchema 将 ToolSchema 转换为 JSON Schema 格式
 func (t *ToolSchema) ToJSONSchema() (string, error) {
 	data, err := json.MarshalIndent(t, "", "  ")
@@ -0,0 +1,292 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func echoHandler(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	return params, nil
+}
+
+func TestMCPAdapter_RegisterTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		toolName string
+		schema   ToolSchema
+		handler  ToolHandler
+		wantErr  error
+	}{
+		{
+			name:     "注册成功并补全名称",
+			toolName: "echo",
+			schema:   ToolSchema{Description: "echo"},
+			handler:  echoHandler,
+		},
+		{
+			name:     "名称与 schema 不一致",
+			toolName: "echo",
+			schema:   ToolSchema{Name: "other"},
+			handler:  echoHandler,
+			wantErr:  ErrToolNameMismatch,
+		},
+		{
+			name:     "非法名称",
+			toolName: "bad name",
+			handler:  echoHandler,
+			wantErr:  ErrInvalidToolName,
+		},
+		{
+			name:     "处理函数为空",
+			toolName: "echo",
+			wantErr:  ErrNilHandler,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewMCPAdapter()
+			err := a.RegisterTool(tt.toolName, tt.schema, tt.handler)
+
+			if tt.wantErr != nil {
+				testutil.AssertEqual(t, errors.Is(err, tt.wantErr), true)
+				testutil.AssertEqual(t, len(a.ListTools()), 0)
+			} else {
+				testutil.AssertNoError(t, err)
+				testutil.AssertEqual(t, a.ListTools()[0].Name, tt.toolName)
+			}
+		})
+	}
+}
+
+func TestMCPAdapter_DuplicateReplaceUnregister(t *testing.T) {
+	a := NewMCPAdapter()
+
+	var changes int
+	cancel := a.Subscribe(func(n Notification) {
+		testutil.AssertEqual(t, n.Method, MethodToolsListChanged)
+		changes++
+	})
+	defer cancel()
+
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{Description: "v1"}, echoHandler))
+
+	err := a.RegisterTool("echo", ToolSchema{Description: "v2"}, echoHandler)
+	testutil.AssertEqual(t, errors.Is(err, ErrToolExists), true)
+
+	testutil.AssertNoError(t, a.ReplaceTool("echo", ToolSchema{Description: "v2"}, echoHandler))
+	testutil.AssertEqual(t, a.ListTools()[0].Description, "v2")
+
+	testutil.AssertNoError(t, a.UnregisterTool("echo"))
+	testutil.AssertEqual(t, errors.Is(a.UnregisterTool("echo"), ErrToolNotFound), true)
+
+	resp, err := a.HandleRequest(context.Background(), &MCPRequest{Tool: "echo"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, resp.Success, false)
+
+	// 注册、替换、注销各触发一次通知，失败的操作不触发
+	testutil.AssertEqual(t, changes, 3)
+}
+
+func TestMCPAdapter_ListToolsSorted(t *testing.T) {
+	a := NewMCPAdapter()
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		testutil.AssertNoError(t, a.RegisterTool(name, ToolSchema{}, echoHandler))
+	}
+
+	var names []string
+	for _, tool := range a.ListTools() {
+		names = append(names, tool.Name)
+	}
+	testutil.AssertEqual(t, names, []string{"alpha", "mid", "zeta"})
+}
+
+func TestMCPAdapter_Concurrent(t *testing.T) {
+	a := NewMCPAdapter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("tool_%d", i)
+			testutil.AssertNoError(t, a.RegisterTool(name, ToolSchema{}, echoHandler))
+			_, _ = a.HandleRequest(context.Background(), &MCPRequest{Tool: name})
+			_ = a.ListTools()
+			if i%2 == 0 {
+				testutil.AssertNoError(t, a.UnregisterTool(name))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	testutil.AssertEqual(t, len(a.ListTools()), 25)
+}
+
+func TestMCPAdapter_Interceptors(t *testing.T) {
+	a := NewMCPAdapter()
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(next ToolHandler) ToolHandler {
+			return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				info, _ := CallInfoFromContext(ctx)
+				order = append(order, name+":"+info.Tool)
+				return next(ctx, params)
+			}
+		}
+	}
+	a.Use(Recovery(), trace("outer"), Timeout(50*time.Millisecond, map[string]time.Duration{"slow": 10 * time.Millisecond}), trace("inner"))
+
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{}, echoHandler))
+	testutil.AssertNoError(t, a.RegisterTool("boom", ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		panic("boom")
+	}))
+	testutil.AssertNoError(t, a.RegisterTool("slow", ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	}))
+
+	_, err := a.CallTool(context.Background(), "echo", nil)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, order, []string{"outer:echo", "inner:echo"})
+
+	_, err = a.CallTool(context.Background(), "boom", nil)
+	testutil.AssertEqual(t, errors.Is(err, ErrToolPanic), true)
+
+	_, err = a.CallTool(context.Background(), "slow", nil)
+	testutil.AssertEqual(t, errors.Is(err, ErrToolTimeout), true)
+}
+
+func TestRedactArgs(t *testing.T) {
+	params := map[string]interface{}{
+		"user":     "alice",
+		"Password": "p@ss",
+		"nested": map[string]interface{}{
+			"api_key": "k",
+			"items":   []interface{}{map[string]interface{}{"token": "t", "id": 1}},
+		},
+	}
+
+	got := RedactArgs(params)
+	want := map[string]interface{}{
+		"user":     "alice",
+		"Password": "***",
+		"nested": map[string]interface{}{
+			"api_key": "***",
+			"items":   []interface{}{map[string]interface{}{"token": "***", "id": 1}},
+		},
+	}
+	testutil.AssertEqual(t, got, want)
+	testutil.AssertEqual(t, params["Password"], "p@ss")
+}
+
+func TestMCPAdapter_Resources(t *testing.T) {
+	a := NewMCPAdapter()
+
+	static := func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		return []ResourceContents{{URI: uri, Text: "static"}}, nil
+	}
+	user := func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		return []ResourceContents{{URI: uri, Text: "user " + vars["id"]}}, nil
+	}
+
+	testutil.AssertNoError(t, a.RegisterResource(Resource{URI: "db://users/me", Name: "me"}, static))
+	testutil.AssertNoError(t, a.RegisterResourceTemplate(ResourceTemplate{URITemplate: "db://users/{id}", Name: "user"}, user))
+	testutil.AssertEqual(t, errors.Is(a.RegisterResource(Resource{URI: "db://users/me"}, static), ErrResourceExists), true)
+	testutil.AssertEqual(t, errors.Is(a.RegisterResource(Resource{URI: "no-scheme"}, static), ErrInvalidResourceURI), true)
+
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr error
+	}{
+		{uri: "db://users/me", want: "static"},
+		{uri: "db://users/42", want: "user 42"},
+		{uri: "db://users/a%20b", want: "user a b"},
+		{uri: "db://users/42/orders", wantErr: ErrResourceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			contents, err := a.ReadResource(context.Background(), tt.uri)
+			if tt.wantErr != nil {
+				testutil.AssertEqual(t, errors.Is(err, tt.wantErr), true)
+				return
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, contents[0].Text, tt.want)
+		})
+	}
+
+	var updated []string
+	cancel := a.Subscribe(func(n Notification) {
+		if n.Method == MethodResourcesUpdated {
+			updated = append(updated, n.Params["uri"].(string))
+		}
+	})
+	defer cancel()
+	a.NotifyResourceUpdated("db://users/42")
+	testutil.AssertEqual(t, updated, []string{"db://users/42"})
+
+	testutil.AssertNoError(t, a.UnregisterResource("db://users/{id}"))
+	testutil.AssertEqual(t, len(a.ListResourceTemplates()), 0)
+}
+
+func TestMCPAdapter_Prompts(t *testing.T) {
+	a := NewMCPAdapter()
+
+	dir := t.TempDir()
+	file := `---
+description: 汇总订单
+arguments:
+  - name: date
+    required: true
+  - name: limit
+    type: integer
+---
+汇总 {{.date}} 的订单{{if .limit}}，最多 {{.limit}} 条{{end}}。
+`
+	testutil.AssertNoError(t, os.WriteFile(filepath.Join(dir, "summarize_orders.md"), []byte(file), 0644))
+
+	n, err := LoadPromptsDir(a, dir)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 1)
+	testutil.AssertEqual(t, a.ListPrompts()[0].Name, "summarize_orders")
+
+	tests := []struct {
+		name    string
+		args    map[string]string
+		want    string
+		wantErr error
+	}{
+		{name: "必填参数", args: map[string]string{"date": "2026-01-25"}, want: "汇总 2026-01-25 的订单。"},
+		{name: "类型参数", args: map[string]string{"date": "today", "limit": "10"}, want: "汇总 today 的订单，最多 10 条。"},
+		{name: "缺少必填参数", args: map[string]string{}, wantErr: ErrInvalidPromptArgs},
+		{name: "类型错误", args: map[string]string{"date": "today", "limit": "ten"}, wantErr: ErrInvalidPromptArgs},
+		{name: "未知参数", args: map[string]string{"date": "today", "other": "x"}, wantErr: ErrInvalidPromptArgs},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := a.GetPrompt(context.Background(), "summarize_orders", tt.args)
+			if tt.wantErr != nil {
+				testutil.AssertEqual(t, errors.Is(err, tt.wantErr), true)
+				return
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, result.Messages[0].Role, RoleUser)
+			testutil.AssertEqual(t, result.Messages[0].Content.Text, tt.want)
+		})
+	}
+
+	_, err = a.GetPrompt(context.Background(), "missing", nil)
+	testutil.AssertEqual(t, errors.Is(err, ErrPromptNotFound), true)
+}
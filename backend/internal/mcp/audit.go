@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// AuditScope 查询审计日志所需的权限
+const AuditScope = "mcp:audit"
+
+// AuditRecord 工具调用审计记录
+type AuditRecord struct {
+	ID         string                 `json:"id"`
+	Time       time.Time              `json:"time"`
+	Subject    string                 `json:"subject,omitempty"`
+	AuthMethod string                 `json:"auth_method,omitempty"`
+	Tool       string                 `json:"tool"`
+	Args       map[string]interface{} `json:"args,omitempty"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+}
+
+// AuditQuery 审计记录查询条件，零值字段不参与过滤
+type AuditQuery struct {
+	Tool    string
+	Subject string
+	Success *bool
+	Since   time.Time
+	Limit   int
+}
+
+// defaultAuditQueryLimit 查询默认返回条数
+const defaultAuditQueryLimit = 100
+
+// matches 记录是否满足查询条件
+func (q AuditQuery) matches(rec *AuditRecord) bool {
+	if q.Tool != "" && rec.Tool != q.Tool {
+		return false
+	}
+	if q.Subject != "" && rec.Subject != q.Subject {
+		return false
+	}
+	if q.Success != nil && rec.Success != *q.Success {
+		return false
+	}
+	if !q.Since.IsZero() && rec.Time.Before(q.Since) {
+		return false
+	}
+	return true
+}
+
+// limit 返回有效的条数限制
+func (q AuditQuery) limit() int {
+	if q.Limit <= 0 {
+		return defaultAuditQueryLimit
+	}
+	return q.Limit
+}
+
+// AuditSink 审计记录存储
+type AuditSink interface {
+	// Write 写入一条审计记录
+	Write(ctx context.Context, rec *AuditRecord) error
+
+	// Query 按条件查询审计记录，按时间倒序返回
+	Query(ctx context.Context, q AuditQuery) ([]AuditRecord, error)
+
+	// Close 释放资源
+	Close() error
+}
+
+// Audit 将每次工具调用（含参数脱敏后的内容、结果与耗时）写入审计存储。
+// 应位于拦截器链最外层，以便记录 panic 与鉴权失败；写入失败只记录日志，不影响调用结果。
+func Audit(sink AuditSink, redactFields ...string) Interceptor {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (result interface{}, err error) {
+			info, _ := CallInfoFromContext(ctx)
+			start := time.Now()
+
+			defer func() {
+				rec := &AuditRecord{
+					ID:         randomID(),
+					Time:       start.UTC(),
+					Tool:       info.Tool,
+					Args:       RedactArgs(params, redactFields...),
+					Success:    err == nil,
+					DurationMs: time.Since(start).Milliseconds(),
+				}
+				if err != nil {
+					rec.Error = err.Error()
+				}
+				if p, ok := PrincipalFromContext(ctx); ok {
+					rec.Subject = p.Subject
+					rec.AuthMethod = p.Method
+				}
+
+				// 使用独立的 context，避免调用被取消后审计记录丢失
+				if werr := sink.Write(context.WithoutCancel(ctx), rec); werr != nil {
+					log.Printf("Failed to write MCP audit record: %v", werr)
+				}
+			}()
+
+			return next(ctx, params)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog 审计记录数据库模型
+type AuditLog struct {
+	ID         string    `gorm:"primaryKey;size:32"`
+	Time       time.Time `gorm:"index"`
+	Subject    string    `gorm:"size:128;index"`
+	AuthMethod string    `gorm:"size:16"`
+	Tool       string    `gorm:"size:128;index"`
+	Args       string    `gorm:"type:text"`
+	Success    bool
+	Error      string `gorm:"type:text"`
+	DurationMs int64
+}
+
+// TableName 表名
+func (AuditLog) TableName() string {
+	return "mcp_audit_logs"
+}
+
+// DBAuditSink 基于 GORM 的数据库审计存储
+type DBAuditSink struct {
+	db        *gorm.DB
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+// NewDBAuditSink 创建数据库审计存储并自动迁移表结构，retention <= 0 表示永久保留
+func NewDBAuditSink(db *gorm.DB, retention time.Duration) (*DBAuditSink, error) {
+	if err := db.AutoMigrate(&AuditLog{}); err != nil {
+		return nil, err
+	}
+	return &DBAuditSink{db: db, retention: retention}, nil
+}
+
+// Write 写入一条记录，并按间隔清理过期记录
+func (s *DBAuditSink) Write(ctx context.Context, rec *AuditRecord) error {
+	args, err := json.Marshal(rec.Args)
+	if err != nil {
+		return err
+	}
+
+	row := AuditLog{
+		ID:         rec.ID,
+		Time:       rec.Time,
+		Subject:    rec.Subject,
+		AuthMethod: rec.AuthMethod,
+		Tool:       rec.Tool,
+		Args:       string(args),
+		Success:    rec.Success,
+		Error:      rec.Error,
+		DurationMs: rec.DurationMs,
+	}
+	if err := s.db.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	return s.prune(ctx)
+}
+
+// Query 按条件查询，按时间倒序返回
+func (s *DBAuditSink) Query(ctx context.Context, q AuditQuery) ([]AuditRecord, error) {
+	tx := s.db.WithContext(ctx).Model(&AuditLog{})
+	if q.Tool != "" {
+		tx = tx.Where("tool = ?", q.Tool)
+	}
+	if q.Subject != "" {
+		tx = tx.Where("subject = ?", q.Subject)
+	}
+	if q.Success != nil {
+		tx = tx.Where("success = ?", *q.Success)
+	}
+	if !q.Since.IsZero() {
+		tx = tx.Where("time >= ?", q.Since)
+	}
+
+	var rows []AuditLog
+	if err := tx.Order("time DESC").Limit(q.limit()).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]AuditRecord, 0, len(rows))
+	for _, row := range rows {
+		rec := AuditRecord{
+			ID:         row.ID,
+			Time:       row.Time,
+			Subject:    row.Subject,
+			AuthMethod: row.AuthMethod,
+			Tool:       row.Tool,
+			Success:    row.Success,
+			Error:      row.Error,
+			DurationMs: row.DurationMs,
+		}
+		_ = json.Unmarshal([]byte(row.Args), &rec.Args)
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Close 数据库连接由调用方管理，此处无需释放
+func (s *DBAuditSink) Close() error {
+	return nil
+}
+
+// prune 删除超过保留期的记录
+func (s *DBAuditSink) prune(ctx context.Context) error {
+	if s.retention <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastPrune = time.Now()
+	s.mu.Unlock()
+
+	return s.db.WithContext(ctx).Where("time < ?", time.Now().Add(-s.retention)).Delete(&AuditLog{}).Error
+}
@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pruneInterval 按保留期清理过期记录的最小间隔
+const pruneInterval = time.Hour
+
+// FileAuditSink 以 JSONL 格式追加写入文件的审计存储
+type FileAuditSink struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	retention time.Duration
+	lastPrune time.Time
+}
+
+// NewFileAuditSink 创建文件审计存储，retention <= 0 表示永久保留
+func NewFileAuditSink(path string, retention time.Duration) (*FileAuditSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create audit log dir: %w", err)
+	}
+
+	s := &FileAuditSink{path: path, retention: retention}
+	if err := s.prune(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open 以追加模式打开审计文件
+func (s *FileAuditSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	s.file = f
+	return nil
+}
+
+// Write 追加一条记录，并按间隔清理过期记录
+func (s *FileAuditSink) Write(ctx context.Context, rec *AuditRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.retention > 0 && s.file != nil && time.Since(s.lastPrune) >= pruneInterval {
+		if err := s.file.Close(); err != nil {
+			log.Printf("Failed to close MCP audit log before pruning: %v", err)
+		}
+		s.file = nil
+		// 清理失败不影响写入，下次写入时重试
+		if err := s.prune(time.Now()); err != nil {
+			log.Printf("Failed to prune MCP audit log: %v", err)
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.file.Write(append(data, '\n'))
+	return err
+}
+
+// Query 读取文件并按条件过滤，按时间倒序返回
+func (s *FileAuditSink) Query(ctx context.Context, q AuditQuery) ([]AuditRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []AuditRecord
+	err := s.scan(func(rec *AuditRecord) {
+		if q.matches(rec) {
+			records = append(records, *rec)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.After(records[j].Time)
+	})
+	if len(records) > q.limit() {
+		records = records[:q.limit()]
+	}
+	return records, nil
+}
+
+// Close 关闭文件
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// scan 逐行解析审计文件，跳过无法解析的行
+func (s *FileAuditSink) scan(fn func(rec *AuditRecord)) error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var rec AuditRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil {
+			fn(&rec)
+		}
+	}
+	return scanner.Err()
+}
+
+// prune 重写文件，丢弃超过保留期的记录，成功后更新清理时间（调用方需保证文件未以写模式打开）
+func (s *FileAuditSink) prune(now time.Time) error {
+	if s.retention <= 0 {
+		s.lastPrune = now
+		return nil
+	}
+
+	cutoff := now.Add(-s.retention)
+	tmp := s.path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+	err = s.scan(func(rec *AuditRecord) {
+		if rec.Time.Before(cutoff) {
+			return
+		}
+		if data, merr := json.Marshal(rec); merr == nil {
+			w.Write(append(data, '\n'))
+		}
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("prune audit log: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("prune audit log: %w", err)
+	}
+	s.lastPrune = now
+	return nil
+}
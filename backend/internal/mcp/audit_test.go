@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestAudit_FileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path, 0)
+	testutil.AssertNoError(t, err)
+	defer sink.Close()
+
+	a := NewMCPAdapter()
+	a.Use(Audit(sink), Recovery())
+	testutil.AssertNoError(t, a.RegisterTool("login", ToolSchema{}, echoHandler))
+	testutil.AssertNoError(t, a.RegisterTool("fail", ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}))
+
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "ci", Method: AuthMethodAPIKey})
+	_, _ = a.CallTool(ctx, "login", map[string]interface{}{"user": "alice", "password": "p@ss"})
+	_, _ = a.CallTool(ctx, "fail", nil)
+
+	records, err := sink.Query(context.Background(), AuditQuery{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(records), 2)
+
+	login, err := sink.Query(context.Background(), AuditQuery{Tool: "login"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, login[0].Subject, "ci")
+	testutil.AssertEqual(t, login[0].Args["password"], "***")
+	testutil.AssertEqual(t, login[0].Success, true)
+
+	failed := false
+	errs, err := sink.Query(context.Background(), AuditQuery{Success: &failed})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(errs), 1)
+	testutil.AssertEqual(t, errs[0].Error, "boom")
+}
+
+func TestAudit_FileSinkRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path, 0)
+	testutil.AssertNoError(t, err)
+
+	ctx := context.Background()
+	testutil.AssertNoError(t, sink.Write(ctx, &AuditRecord{ID: "old", Tool: "t", Time: time.Now().Add(-48 * time.Hour)}))
+	testutil.AssertNoError(t, sink.Write(ctx, &AuditRecord{ID: "new", Tool: "t", Time: time.Now()}))
+	testutil.AssertNoError(t, sink.Close())
+
+	// 重新打开时按保留期清理
+	sink, err = NewFileAuditSink(path, 24*time.Hour)
+	testutil.AssertNoError(t, err)
+	defer sink.Close()
+
+	records, err := sink.Query(ctx, AuditQuery{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(records), 1)
+	testutil.AssertEqual(t, records[0].ID, "new")
+}
+
+func TestAudit_FileSinkPruneFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path, 24*time.Hour)
+	testutil.AssertNoError(t, err)
+	defer sink.Close()
+
+	// 临时文件路径被目录占用，清理失败
+	testutil.AssertNoError(t, os.Mkdir(path+".tmp", 0755))
+	sink.lastPrune = time.Now().Add(-2 * pruneInterval)
+
+	ctx := context.Background()
+	testutil.AssertNoError(t, sink.Write(ctx, &AuditRecord{ID: "a", Tool: "t", Time: time.Now()}))
+	testutil.AssertEqual(t, sink.lastPrune.Before(time.Now().Add(-pruneInterval)), true)
+
+	// 清理失败后文件仍可写入，恢复后下次写入重新清理
+	testutil.AssertNoError(t, os.Remove(path+".tmp"))
+	testutil.AssertNoError(t, sink.Write(ctx, &AuditRecord{ID: "b", Tool: "t", Time: time.Now()}))
+	testutil.AssertEqual(t, time.Since(sink.lastPrune) < pruneInterval, true)
+
+	records, err := sink.Query(ctx, AuditQuery{})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(records), 2)
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 认证方式
+const (
+	AuthMethodAPIKey = "api_key"
+	AuthMethodBearer = "bearer"
+)
+
+// APIKeyHeader API Key 请求头
+const APIKeyHeader = "X-API-Key"
+
+// 认证错误定义
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrInvalidToken    = errors.New("invalid token")
+)
+
+// Principal 已认证的调用方
+type Principal struct {
+	Subject string   `json:"subject"`
+	Method  string   `json:"method"`
+	Scopes  []string `json:"scopes,omitempty"`
+}
+
+// HasScopes 是否拥有全部所需权限
+func (p *Principal) HasScopes(required ...string) bool {
+	granted := make(map[string]bool, len(p.Scopes))
+	for _, s := range p.Scopes {
+		granted[s] = true
+	}
+	for _, s := range required {
+		if !granted[s] {
+			return false
+		}
+	}
+	return true
+}
+
+type principalKey struct{}
+
+// WithPrincipal 将调用方写入 context
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext 读取调用方
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// ScopeError 权限不足错误，Resource 或 Prompt 非空时表示读取资源或获取提示词被拒绝
+type ScopeError struct {
+	Tool     string
+	Resource string
+	Prompt   string
+	Required []string
+}
+
+func (e *ScopeError) Error() string {
+	target := "tool " + e.Tool
+	switch {
+	case e.Resource != "":
+		target = "resource " + e.Resource
+	case e.Prompt != "":
+		target = "prompt " + e.Prompt
+	}
+	return fmt.Sprintf("insufficient scope for %s: requires %s", target, strings.Join(e.Required, " "))
+}
+
+// Authorize 校验 context 中的调用方是否拥有工具声明的全部权限；未声明权限的工具不做限制
+func Authorize(ctx context.Context, schema ToolSchema) error {
+	return authorizeScopes(ctx, &ScopeError{Tool: schema.Name, Required: schema.Scopes})
+}
+
+// AuthorizeResource 校验调用方是否拥有读取或订阅资源所需的权限；静态资源优先于模板，未声明权限或不存在的资源不做限制
+func AuthorizeResource(ctx context.Context, adapter MCPAdapter, uri string) error {
+	return authorizeScopes(ctx, &ScopeError{Resource: uri, Required: resourceScopes(adapter, uri)})
+}
+
+// AuthorizePrompt 校验调用方是否拥有获取提示词所需的权限；未声明权限或不存在的提示词不做限制
+func AuthorizePrompt(ctx context.Context, adapter MCPAdapter, name string) error {
+	var scopes []string
+	for _, p := range adapter.ListPrompts() {
+		if p.Name == name {
+			scopes = p.Scopes
+			break
+		}
+	}
+	return authorizeScopes(ctx, &ScopeError{Prompt: name, Required: scopes})
+}
+
+// authorizeScopes 校验调用方是否拥有 denied.Required 中的全部权限，不满足时返回 denied
+func authorizeScopes(ctx context.Context, denied *ScopeError) error {
+	if len(denied.Required) == 0 {
+		return nil
+	}
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	if !p.HasScopes(denied.Required...) {
+		return denied
+	}
+	return nil
+}
+
+// resourceScopes 查找 URI 对应资源声明的权限，匹配顺序与资源读取一致
+func resourceScopes(adapter MCPAdapter, uri string) []string {
+	for _, r := range adapter.ListResources() {
+		if r.URI == uri {
+			return r.Scopes
+		}
+	}
+	for _, t := range adapter.ListResourceTemplates() {
+		matcher, err := parseURITemplate(t.URITemplate)
+		if err != nil {
+			continue
+		}
+		if _, ok := matcher.match(uri); ok {
+			return t.Scopes
+		}
+	}
+	return nil
+}
+
+// RequireScopes 按工具声明的权限进行授权的拦截器
+func RequireScopes() Interceptor {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			info, _ := CallInfoFromContext(ctx)
+			if err := Authorize(ctx, info.Schema); err != nil {
+				return nil, err
+			}
+			return next(ctx, params)
+		}
+	}
+}
+
+// ProtectedResourceMetadata OAuth 2.0 受保护资源元数据（RFC 9728）
+type ProtectedResourceMetadata struct {
+	Resource               string   `json:"resource"`
+	AuthorizationServers   []string `json:"authorization_servers,omitempty"`
+	ScopesSupported        []string `json:"scopes_supported,omitempty"`
+	BearerMethodsSupported []string `json:"bearer_methods_supported"`
+}
+
+// ScopesSupported 汇总所有工具、资源与提示词声明的权限
+func ScopesSupported(adapter MCPAdapter) []string {
+	seen := make(map[string]bool)
+	var scopes []string
+	add := func(declared []string) {
+		for _, s := range declared {
+			if !seen[s] {
+				seen[s] = true
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	for _, tool := range adapter.ListTools() {
+		add(tool.Scopes)
+	}
+	for _, r := range adapter.ListResources() {
+		add(r.Scopes)
+	}
+	for _, t := range adapter.ListResourceTemplates() {
+		add(t.Scopes)
+	}
+	for _, p := range adapter.ListPrompts() {
+		add(p.Scopes)
+	}
+	return scopes
+}
@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestRequireScopes(t *testing.T) {
+	a := NewMCPAdapter()
+	a.Use(RequireScopes())
+	testutil.AssertNoError(t, a.RegisterTool("secure", ToolSchema{Scopes: []string{"orders:write"}}, echoHandler))
+	testutil.AssertNoError(t, a.RegisterTool("open", ToolSchema{}, echoHandler))
+
+	_, err := a.CallTool(context.Background(), "secure", nil)
+	testutil.AssertEqual(t, errors.Is(err, ErrUnauthenticated), true)
+
+	reader := WithPrincipal(context.Background(), &Principal{Subject: "r", Scopes: []string{"orders:read"}})
+	_, err = a.CallTool(reader, "secure", nil)
+	var scopeErr *ScopeError
+	testutil.AssertEqual(t, errors.As(err, &scopeErr), true)
+	testutil.AssertEqual(t, scopeErr.Required, []string{"orders:write"})
+
+	writer := WithPrincipal(context.Background(), &Principal{Subject: "w", Scopes: []string{"orders:write"}})
+	_, err = a.CallTool(writer, "secure", nil)
+	testutil.AssertNoError(t, err)
+
+	_, err = a.CallTool(context.Background(), "open", nil)
+	testutil.AssertNoError(t, err)
+}
+
+func TestAuthorizeResourceAndPrompt(t *testing.T) {
+	a := NewMCPAdapter()
+	read := func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		return nil, nil
+	}
+	testutil.AssertNoError(t, a.RegisterResource(Resource{URI: "db://stats", Name: "stats", Scopes: []string{"db:admin"}}, read))
+	testutil.AssertNoError(t, a.RegisterResourceTemplate(ResourceTemplate{URITemplate: "db://users/{id}", Name: "user", Scopes: []string{"users:read"}}, read))
+	testutil.AssertNoError(t, a.RegisterResource(Resource{URI: "db://open", Name: "open"}, read))
+	testutil.AssertNoError(t, a.RegisterPrompt(Prompt{Name: "secret_prompt", Scopes: []string{"prompts:admin"}}, func(ctx context.Context, args map[string]string) (*PromptResult, error) {
+		return &PromptResult{}, nil
+	}))
+
+	anonymous := context.Background()
+	reader := WithPrincipal(context.Background(), &Principal{Subject: "r", Scopes: []string{"users:read"}})
+
+	testutil.AssertEqual(t, errors.Is(AuthorizeResource(anonymous, a, "db://users/1"), ErrUnauthenticated), true)
+	testutil.AssertNoError(t, AuthorizeResource(reader, a, "db://users/1"))
+	testutil.AssertNoError(t, AuthorizeResource(anonymous, a, "db://open"))
+	testutil.AssertNoError(t, AuthorizeResource(anonymous, a, "db://missing"))
+
+	var scopeErr *ScopeError
+	testutil.AssertEqual(t, errors.As(AuthorizeResource(reader, a, "db://stats"), &scopeErr), true)
+	testutil.AssertEqual(t, scopeErr.Resource, "db://stats")
+	testutil.AssertEqual(t, scopeErr.Required, []string{"db:admin"})
+
+	testutil.AssertEqual(t, errors.As(AuthorizePrompt(reader, a, "secret_prompt"), &scopeErr), true)
+	testutil.AssertEqual(t, scopeErr.Prompt, "secret_prompt")
+	testutil.AssertNoError(t, AuthorizePrompt(reader, a, "missing"))
+
+	testutil.AssertEqual(t, ScopesSupported(a), []string{"db:admin", "users:read", "prompts:admin"})
+}
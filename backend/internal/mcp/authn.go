@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKey API Key 凭证
+type APIKey struct {
+	Name   string
+	Key    string
+	Scopes []string
+}
+
+// AuthOptions 认证器配置
+type AuthOptions struct {
+	APIKeys  []APIKey
+	Verifier *JWTVerifier
+}
+
+// Authenticator HTTP 请求认证器，支持 API Key 与 Bearer Token（JWT）
+type Authenticator struct {
+	apiKeys  []APIKey
+	verifier *JWTVerifier
+}
+
+// NewAuthenticator 创建认证器
+func NewAuthenticator(opts AuthOptions) *Authenticator {
+	return &Authenticator{apiKeys: opts.APIKeys, verifier: opts.Verifier}
+}
+
+// Authenticate 认证请求，未携带凭证时返回 ErrUnauthenticated，凭证无效时返回 ErrInvalidToken
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return a.authenticateAPIKey(key)
+	}
+
+	auth := r.Header.Get("Authorization")
+	if scheme, token, ok := strings.Cut(auth, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		if a.verifier == nil {
+			return nil, fmt.Errorf("%w: bearer tokens are not accepted", ErrInvalidToken)
+		}
+		claims, err := a.verifier.Verify(strings.TrimSpace(token))
+		if err != nil {
+			return nil, err
+		}
+		return &Principal{Subject: claims.Subject, Method: AuthMethodBearer, Scopes: claims.Scopes()}, nil
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// authenticateAPIKey 以常量时间比较 API Key
+func (a *Authenticator) authenticateAPIKey(key string) (*Principal, error) {
+	for _, k := range a.apiKeys {
+		if k.Key != "" && subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+			return &Principal{Subject: k.Name, Method: AuthMethodAPIKey, Scopes: k.Scopes}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown api key", ErrInvalidToken)
+}
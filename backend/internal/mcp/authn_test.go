@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func signHS256(t *testing.T, secret string, header, claims map[string]interface{}) string {
+	t.Helper()
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	verifier, err := NewHS256Verifier("s3cret", "https://auth.example.com", "mcp")
+	testutil.AssertNoError(t, err)
+	auth := NewAuthenticator(AuthOptions{
+		APIKeys:  []APIKey{{Name: "ci", Key: "k1", Scopes: []string{"health:read"}}},
+		Verifier: verifier,
+	})
+
+	hs := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	valid := map[string]interface{}{
+		"sub": "agent", "iss": "https://auth.example.com", "aud": []string{"mcp"},
+		"exp": time.Now().Add(time.Hour).Unix(), "scope": "health:read orders:write",
+	}
+	expired := map[string]interface{}{"sub": "agent", "iss": "https://auth.example.com", "aud": "mcp", "exp": time.Now().Add(-time.Minute).Unix()}
+	exp := time.Now().Add(time.Hour).Unix()
+	wrongAud := map[string]interface{}{"sub": "agent", "iss": "https://auth.example.com", "aud": "other", "exp": exp}
+	noAud := map[string]interface{}{"sub": "agent", "iss": "https://auth.example.com", "exp": exp}
+	noExp := map[string]interface{}{"sub": "agent", "iss": "https://auth.example.com", "aud": "mcp"}
+
+	tests := []struct {
+		name        string
+		header      string
+		value       string
+		wantSubject string
+		wantScopes  []string
+		wantErr     error
+	}{
+		{name: "API Key", header: APIKeyHeader, value: "k1", wantSubject: "ci", wantScopes: []string{"health:read"}},
+		{name: "未知 API Key", header: APIKeyHeader, value: "k2", wantErr: ErrInvalidToken},
+		{name: "有效 JWT", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", hs, valid), wantSubject: "agent", wantScopes: []string{"health:read", "orders:write"}},
+		{name: "过期 JWT", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", hs, expired), wantErr: ErrInvalidToken},
+		{name: "audience 不匹配", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", hs, wrongAud), wantErr: ErrInvalidToken},
+		{name: "缺少 audience", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", hs, noAud), wantErr: ErrInvalidToken},
+		{name: "缺少 exp", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", hs, noExp), wantErr: ErrInvalidToken},
+		{name: "签名错误", header: "Authorization", value: "Bearer " + signHS256(t, "other", hs, valid), wantErr: ErrInvalidToken},
+		{name: "alg none", header: "Authorization", value: "Bearer " + signHS256(t, "s3cret", map[string]interface{}{"alg": "none"}, valid), wantErr: ErrInvalidToken},
+		{name: "未携带凭证", wantErr: ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			p, err := auth.Authenticate(req)
+			if tt.wantErr != nil {
+				testutil.AssertEqual(t, errors.Is(err, tt.wantErr), true)
+				return
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, p.Subject, tt.wantSubject)
+			testutil.AssertEqual(t, p.Scopes, tt.wantScopes)
+		})
+	}
+}
+
+func TestNewVerifier_RequiresAudience(t *testing.T) {
+	_, err := NewHS256Verifier("s3cret", "", "")
+	testutil.AssertError(t, err)
+	_, err = NewRS256Verifier("missing.pem", "", "")
+	testutil.AssertEqual(t, errors.Is(err, errMissingAudience), true)
+}
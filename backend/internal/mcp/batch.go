@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency 批量执行的默认并发数
+const DefaultBatchConcurrency = 4
+
+// DefaultMaxBatchSize 单批最多包含的调用数
+const DefaultMaxBatchSize = 100
+
+// DefaultMaxBatchConcurrency 客户端可请求的最大并发数
+const DefaultMaxBatchConcurrency = 16
+
+// ErrBatchAborted 批量执行因前序调用失败而中止
+var ErrBatchAborted = errors.New("skipped: batch aborted after a previous call failed")
+
+// ErrBatchTooLarge 批量请求包含的调用数超过上限
+var ErrBatchTooLarge = errors.New("batch too large")
+
+// BatchLimits 批量执行的服务端限制，零值使用默认值
+type BatchLimits struct {
+	MaxSize        int // 单批最多包含的调用数
+	MaxConcurrency int // 并发数上限，客户端请求的并发数超过时按上限执行
+}
+
+// check 校验批量请求的大小
+func (l BatchLimits) check(n int) error {
+	max := l.MaxSize
+	if max <= 0 {
+		max = DefaultMaxBatchSize
+	}
+	if n > max {
+		return fmt.Errorf("%w: %d requests, max %d", ErrBatchTooLarge, n, max)
+	}
+	return nil
+}
+
+// concurrency 将请求的并发数限制在上限内，未指定时使用默认值
+func (l BatchLimits) concurrency(requested int) int {
+	max := l.MaxConcurrency
+	if max <= 0 {
+		max = DefaultMaxBatchConcurrency
+	}
+	if requested <= 0 {
+		requested = DefaultBatchConcurrency
+	}
+	if requested > max {
+		return max
+	}
+	return requested
+}
+
+// MCPBatchRequest 批量执行请求
+type MCPBatchRequest struct {
+	Requests    []MCPRequest `json:"requests" binding:"required,min=1"`
+	Concurrency int          `json:"concurrency,omitempty"`   // 并发数，默认 DefaultBatchConcurrency，不超过服务端上限
+	StopOnError bool         `json:"stop_on_error,omitempty"` // 任一调用失败后不再执行未开始的调用，并取消执行中的调用
+}
+
+// MCPBatchResponse 批量执行结果，顺序与请求一致
+type MCPBatchResponse struct {
+	Results []*MCPResponse `json:"results"`
+}
+
+// ExecuteBatch 使用有界 worker 池并发执行多个工具调用，调用数超过上限时返回 ErrBatchTooLarge
+func ExecuteBatch(ctx context.Context, adapter MCPAdapter, req *MCPBatchRequest, limits BatchLimits) (*MCPBatchResponse, error) {
+	if err := limits.check(len(req.Requests)); err != nil {
+		return nil, err
+	}
+	results := make([]*MCPResponse, len(req.Requests))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var aborted bool
+	var mu sync.Mutex
+	run := func(i int) {
+		mu.Lock()
+		skip := aborted
+		mu.Unlock()
+		if skip {
+			results[i] = &MCPResponse{Success: false, Error: ErrBatchAborted.Error()}
+			return
+		}
+
+		resp, err := adapter.HandleRequest(ctx, &req.Requests[i])
+		if err != nil {
+			resp = &MCPResponse{Success: false, Error: err.Error()}
+		}
+		results[i] = resp
+
+		if !resp.Success && req.StopOnError {
+			mu.Lock()
+			aborted = true
+			mu.Unlock()
+			cancel()
+		}
+	}
+
+	forEachConcurrent(len(req.Requests), limits.concurrency(req.Concurrency), run)
+	return &MCPBatchResponse{Results: results}, nil
+}
+
+// HandleBatch 并发处理一批 JSON-RPC 消息，返回的响应与请求顺序一致，通知不产生响应
+func (sess *Session) HandleBatch(ctx context.Context, reqs []*JSONRPCRequest, out NotifyFunc) []*JSONRPCResponse {
+	if len(reqs) == 0 {
+		return []*JSONRPCResponse{InvalidRequestResponse("empty batch")}
+	}
+	limits := sess.server.BatchLimits()
+	if err := limits.check(len(reqs)); err != nil {
+		return []*JSONRPCResponse{InvalidRequestResponse(err.Error())}
+	}
+
+	responses := make([]*JSONRPCResponse, len(reqs))
+	forEachConcurrent(len(reqs), limits.concurrency(0), func(i int) {
+		if reqs[i] == nil {
+			responses[i] = InvalidRequestResponse("invalid request")
+			return
+		}
+		responses[i] = sess.Handle(ctx, reqs[i], out)
+	})
+
+	result := make([]*JSONRPCResponse, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			result = append(result, resp)
+		}
+	}
+	return result
+}
+
+// forEachConcurrent 以最多 concurrency 个 goroutine 按序号执行 fn
+func forEachConcurrent(n, concurrency int, fn func(i int)) {
+	if concurrency > n {
+		concurrency = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestExecuteBatch(t *testing.T) {
+	var running, peak atomic.Int32
+	slow := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return params["n"], nil
+	}
+	fail := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	}
+
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, a.RegisterTool("slow", ToolSchema{}, slow))
+	testutil.AssertNoError(t, a.RegisterTool("fail", ToolSchema{}, fail))
+
+	t.Run("有界并发且结果有序", func(t *testing.T) {
+		req := &MCPBatchRequest{Concurrency: 2}
+		for i := 0; i < 6; i++ {
+			req.Requests = append(req.Requests, MCPRequest{Tool: "slow", Params: map[string]interface{}{"n": i}})
+		}
+
+		resp, err := ExecuteBatch(context.Background(), a, req, BatchLimits{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, len(resp.Results), 6)
+		for i, r := range resp.Results {
+			testutil.AssertEqual(t, r.Success, true)
+			testutil.AssertEqual(t, r.Data, i)
+		}
+		testutil.AssertEqual(t, peak.Load() <= 2, true)
+	})
+
+	t.Run("失败后停止", func(t *testing.T) {
+		req := &MCPBatchRequest{
+			Concurrency: 1,
+			StopOnError: true,
+			Requests: []MCPRequest{
+				{Tool: "slow", Params: map[string]interface{}{"n": 0}},
+				{Tool: "fail"},
+				{Tool: "slow", Params: map[string]interface{}{"n": 2}},
+			},
+		}
+
+		resp, err := ExecuteBatch(context.Background(), a, req, BatchLimits{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, resp.Results[0].Success, true)
+		testutil.AssertEqual(t, resp.Results[1].Error, "boom")
+		testutil.AssertEqual(t, resp.Results[2].Error, ErrBatchAborted.Error())
+	})
+
+	t.Run("并发数不超过服务端上限", func(t *testing.T) {
+		peak.Store(0)
+		req := &MCPBatchRequest{Concurrency: 1000}
+		for i := 0; i < 6; i++ {
+			req.Requests = append(req.Requests, MCPRequest{Tool: "slow", Params: map[string]interface{}{"n": i}})
+		}
+
+		resp, err := ExecuteBatch(context.Background(), a, req, BatchLimits{MaxConcurrency: 2})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, len(resp.Results), 6)
+		testutil.AssertEqual(t, peak.Load() <= 2, true)
+	})
+
+	t.Run("超过大小上限", func(t *testing.T) {
+		req := &MCPBatchRequest{Requests: []MCPRequest{{Tool: "slow"}, {Tool: "slow"}, {Tool: "slow"}}}
+		_, err := ExecuteBatch(context.Background(), a, req, BatchLimits{MaxSize: 2})
+		testutil.AssertEqual(t, errors.Is(err, ErrBatchTooLarge), true)
+	})
+
+	t.Run("默认继续执行", func(t *testing.T) {
+		req := &MCPBatchRequest{Requests: []MCPRequest{{Tool: "fail"}, {Tool: "slow"}}}
+		resp, err := ExecuteBatch(context.Background(), a, req, BatchLimits{})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, resp.Results[0].Success, false)
+		testutil.AssertEqual(t, resp.Results[1].Success, true)
+	})
+}
+
+func TestSession_HandleBatch(t *testing.T) {
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{}, echoHandler))
+	sess := newSession(t, a)
+	ctx := context.Background()
+
+	responses := sess.HandleBatch(ctx, []*JSONRPCRequest{
+		newRequest(1, "tools/call", map[string]interface{}{"name": "echo"}),
+		newRequest(0, "notifications/initialized", nil),
+		newRequest(2, "foo/bar", nil),
+		nil,
+		newRequest(3, "ping", nil),
+	}, nil)
+
+	testutil.AssertEqual(t, len(responses), 4)
+	testutil.AssertEqual(t, string(responses[0].ID), "1")
+	testutil.AssertNil(t, responses[0].Error)
+	testutil.AssertEqual(t, responses[1].Error.Code, CodeMethodNotFound)
+	testutil.AssertEqual(t, responses[2].Error.Code, CodeInvalidRequest)
+	testutil.AssertEqual(t, string(responses[3].ID), "3")
+
+	empty := sess.HandleBatch(ctx, nil, nil)
+	testutil.AssertEqual(t, empty[0].Error.Code, CodeInvalidRequest)
+}
+
+func TestSession_HandleBatchTooLarge(t *testing.T) {
+	server := NewServer(NewMCPAdapter(), Implementation{Name: "test"})
+	server.SetBatchLimits(BatchLimits{MaxSize: 2})
+	sess, err := server.NewSession()
+	testutil.AssertNoError(t, err)
+
+	responses := sess.HandleBatch(context.Background(), []*JSONRPCRequest{
+		newRequest(1, "ping", nil),
+		newRequest(2, "ping", nil),
+		newRequest(3, "ping", nil),
+	}, nil)
+	testutil.AssertEqual(t, len(responses), 1)
+	testutil.AssertEqual(t, responses[0].Error.Code, CodeInvalidRequest)
+}
@@ -0,0 +1,209 @@
+// Package client MCP 客户端：连接远程 MCP 服务（stdio 子进程或 Streamable HTTP），列出并调用其工具
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// ErrNotInitialized 未完成 initialize 握手
+var ErrNotInitialized = errors.New("mcp client not initialized")
+
+// Response 远程服务返回的 JSON-RPC 响应（Result 延迟解析）
+type Response struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *mcp.JSONRPCError `json:"error,omitempty"`
+}
+
+// Transport 客户端传输层，通知消息返回 nil 响应
+type Transport interface {
+	RoundTrip(ctx context.Context, req *mcp.JSONRPCRequest) (*Response, error)
+	Close() error
+}
+
+// Tool 远程工具描述
+type Tool struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *mcp.ToolAnnotations   `json:"annotations,omitempty"`
+}
+
+// Client MCP 客户端
+type Client struct {
+	transport Transport
+	info      mcp.Implementation
+	nextID    atomic.Int64
+
+	initialized  bool
+	serverInfo   mcp.Implementation
+	capabilities map[string]interface{}
+}
+
+// New 创建客户端，使用前需调用 Initialize
+func New(transport Transport, info mcp.Implementation) *Client {
+	return &Client{transport: transport, info: info}
+}
+
+// Connect 基于传输层创建客户端并完成握手
+func Connect(ctx context.Context, transport Transport, info mcp.Implementation) (*Client, error) {
+	c := New(transport, info)
+	if err := c.Initialize(ctx); err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("failed to initialize mcp client: %w", err)
+	}
+	return c, nil
+}
+
+// Initialize 完成 initialize 握手并发送 notifications/initialized
+func (c *Client) Initialize(ctx context.Context) error {
+	var result struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		Capabilities    map[string]interface{} `json:"capabilities"`
+		ServerInfo      mcp.Implementation     `json:"serverInfo"`
+	}
+	params := map[string]interface{}{
+		"protocolVersion": mcp.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      c.info,
+	}
+	if err := c.call(ctx, "initialize", params, &result); err != nil {
+		return err
+	}
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		return err
+	}
+
+	c.serverInfo = result.ServerInfo
+	c.capabilities = result.Capabilities
+	c.initialized = true
+	return nil
+}
+
+// ServerInfo 远程服务实现信息
+func (c *Client) ServerInfo() mcp.Implementation {
+	return c.serverInfo
+}
+
+// ListTools 列出远程工具（自动翻页）
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	if !c.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	var tools []Tool
+	cursor := ""
+	for {
+		var params map[string]interface{}
+		if cursor != "" {
+			params = map[string]interface{}{"cursor": cursor}
+		}
+		var result struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &result); err != nil {
+			return nil, err
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// CallTool 调用远程工具；工具执行失败时返回 IsError 为 true 的结果而非 error
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) (*mcp.CallToolResult, error) {
+	if !c.initialized {
+		return nil, ErrNotInitialized
+	}
+
+	var result mcp.CallToolResult
+	params := map[string]interface{}{"name": name, "arguments": args}
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Ping 检查远程服务是否可用
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// Close 关闭传输层
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
+
+// call 发送请求并解析结果；ctx 取消时通知远程服务取消该请求
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	id := json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10))
+	req, err := newRequest(id, method, params)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.transport.RoundTrip(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil && method != "initialize" {
+			c.cancel(id, ctx.Err())
+		}
+		return fmt.Errorf("mcp %s: %w", method, err)
+	}
+	if resp == nil {
+		return fmt.Errorf("mcp %s: empty response", method)
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("mcp %s: %w", method, resp.Error)
+	}
+	if result == nil || len(resp.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Result, result); err != nil {
+		return fmt.Errorf("mcp %s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+// notify 发送通知
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	req, err := newRequest(nil, method, params)
+	if err != nil {
+		return err
+	}
+	if _, err := c.transport.RoundTrip(ctx, req); err != nil {
+		return fmt.Errorf("mcp %s: %w", method, err)
+	}
+	return nil
+}
+
+// cancel 发送 notifications/cancelled（尽力而为）
+func (c *Client) cancel(id json.RawMessage, reason error) {
+	params := map[string]interface{}{"requestId": id, "reason": reason.Error()}
+	_ = c.notify(context.Background(), "notifications/cancelled", params)
+}
+
+// newRequest 创建 JSON-RPC 请求，id 为空时为通知
+func newRequest(id json.RawMessage, method string, params interface{}) (*mcp.JSONRPCRequest, error) {
+	req := &mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPCVersion, ID: id, Method: method}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("mcp %s: invalid params: %w", method, err)
+		}
+		req.Params = raw
+	}
+	return req, nil
+}
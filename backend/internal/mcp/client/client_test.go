@@ -0,0 +1,274 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+const stdioServerEnv = "MCP_CLIENT_TEST_STDIO_SERVER"
+
+// TestMain 设置环境变量时当前测试二进制作为 stdio MCP 服务运行
+func TestMain(m *testing.M) {
+	if os.Getenv(stdioServerEnv) == "1" {
+		serveStdio()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// newRemoteServer 创建带 echo、fail 两个工具的 MCP 服务端
+func newRemoteServer() *mcp.Server {
+	adapter := mcp.NewMCPAdapter()
+	adapter.RegisterTool("echo", mcp.ToolSchema{Description: "echo"}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return params, nil
+	})
+	adapter.RegisterTool("fail", mcp.ToolSchema{Description: "fail"}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	return mcp.NewServer(adapter, mcp.Implementation{Name: "remote", Version: "1.0.0"})
+}
+
+// serveStdio 按行读取请求并写出响应；响应 tools/list 前先向客户端发起 ping 与未知方法请求
+// （后者复用客户端请求的 ID），客户端答复不正确时返回错误
+func serveStdio() {
+	sess, err := newRemoteServer().NewSession()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req mcp.JSONRPCRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			continue
+		}
+		if req.Method == "tools/list" {
+			if err := pingClient(scanner, req.ID); err != nil {
+				data, _ := json.Marshal(mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPCVersion, ID: req.ID, Error: mcp.NewJSONRPCError(mcp.CodeInternalError, err.Error())})
+				fmt.Println(string(data))
+				continue
+			}
+		}
+		if resp := sess.Handle(context.Background(), &req, nil); resp != nil {
+			data, _ := json.Marshal(resp)
+			fmt.Println(string(data))
+		}
+	}
+}
+
+// pingClient 向客户端发起 ping 与 roots/list 请求并校验答复
+func pingClient(scanner *bufio.Scanner, id json.RawMessage) error {
+	fmt.Println(`{"jsonrpc":"2.0","id":"server-1","method":"ping"}`)
+	fmt.Printf(`{"jsonrpc":"2.0","id":%s,"method":"roots/list"}`+"\n", id)
+
+	replies := make(map[string]Response)
+	for len(replies) < 2 && scanner.Scan() {
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			return err
+		}
+		replies[string(resp.ID)] = resp
+	}
+	if r := replies[`"server-1"`]; r.Error != nil || r.Result == nil {
+		return fmt.Errorf("unexpected ping reply: %+v", r)
+	}
+	if r := replies[string(id)]; r.Error == nil || r.Error.Code != mcp.CodeMethodNotFound {
+		return fmt.Errorf("unexpected roots/list reply: %+v", r)
+	}
+	return nil
+}
+
+// newHTTPServer 最小化的 Streamable HTTP 服务端，Accept 包含 SSE 时以事件流返回
+func newHTTPServer(t *testing.T) *httptest.Server {
+	server := newRemoteServer()
+	var mu sync.Mutex
+	var sess *mcp.Session
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		var req mcp.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		if req.Method == "initialize" {
+			var err error
+			if sess, err = server.NewSession(); err != nil {
+				mu.Unlock()
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set(SessionHeader, sess.ID)
+		} else if sess == nil || r.Header.Get(SessionHeader) != sess.ID {
+			mu.Unlock()
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+		current := sess
+		mu.Unlock()
+
+		resp := current.Handle(r.Context(), &req, nil)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		data, _ := json.Marshal(resp)
+		if req.Method == "tools/call" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	transports := map[string]func(t *testing.T) Transport{
+		"http": func(t *testing.T) Transport {
+			return NewHTTPTransport(newHTTPServer(t).URL, map[string]string{"X-API-Key": "k"}, nil)
+		},
+		"stdio": func(t *testing.T) Transport {
+			tr, err := NewStdioTransport(os.Args[0], nil, []string{stdioServerEnv + "=1"})
+			testutil.AssertNoError(t, err)
+			return tr
+		},
+	}
+
+	for name, newTransport := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c, err := Connect(ctx, newTransport(t), mcp.Implementation{Name: "test", Version: "0.0.1"})
+			testutil.AssertNoError(t, err)
+			defer c.Close()
+
+			testutil.AssertEqual(t, c.ServerInfo().Name, "remote")
+			testutil.AssertNoError(t, c.Ping(ctx))
+
+			tools, err := c.ListTools(ctx)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, len(tools), 2)
+			testutil.AssertEqual(t, tools[0].Name, "echo")
+
+			result, err := c.CallTool(ctx, "echo", map[string]interface{}{"msg": "hi"})
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, result.IsError, false)
+			testutil.AssertEqual(t, result.Content[0].Text, `{"msg":"hi"}`)
+
+			result, err = c.CallTool(ctx, "fail", nil)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, result.IsError, true)
+
+			_, err = c.CallTool(ctx, "missing", nil)
+			var rpcErr *mcp.JSONRPCError
+			testutil.AssertEqual(t, errors.As(err, &rpcErr), true)
+			testutil.AssertEqual(t, rpcErr.Code, mcp.CodeInvalidParams)
+		})
+	}
+}
+
+func TestHTTPTransport_SessionExpired(t *testing.T) {
+	server := newRemoteServer()
+	var inits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req mcp.JSONRPCRequest
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var sess *mcp.Session
+		if req.Method == "initialize" {
+			var err error
+			if sess, err = server.NewSession(); err != nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			inits.Add(1)
+			w.Header().Set(SessionHeader, sess.ID)
+		} else {
+			var ok bool
+			if sess, ok = server.Session(r.Header.Get(SessionHeader)); !ok {
+				http.Error(w, "session not found", http.StatusNotFound)
+				return
+			}
+		}
+
+		resp := sess.Handle(r.Context(), &req, nil)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	tr := NewHTTPTransport(srv.URL, nil, nil)
+	c, err := Connect(ctx, tr, mcp.Implementation{Name: "test", Version: "0.0.1"})
+	testutil.AssertNoError(t, err)
+
+	// 服务端关闭会话（如空闲超时）后，下一次调用重新握手并重试
+	expired := tr.sessionID
+	server.CloseSession(expired)
+
+	result, err := c.CallTool(ctx, "echo", map[string]interface{}{"msg": "hi"})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, result.IsError, false)
+	testutil.AssertEqual(t, inits.Load(), int32(2))
+	testutil.AssertEqual(t, tr.sessionID != expired, true)
+
+	// 未经过 initialize 的传输层无法恢复会话
+	stale := NewHTTPTransport(srv.URL, nil, nil)
+	stale.sessionID = "unknown"
+	err = New(stale, mcp.Implementation{Name: "test"}).Ping(ctx)
+	testutil.AssertEqual(t, errors.Is(err, ErrSessionExpired), true)
+}
+
+func TestClient_NotInitialized(t *testing.T) {
+	c := New(NewHTTPTransport("http://127.0.0.1:0", nil, nil), mcp.Implementation{})
+	_, err := c.ListTools(context.Background())
+	testutil.AssertEqual(t, errors.Is(err, ErrNotInitialized), true)
+}
+
+func TestFederate(t *testing.T) {
+	ctx := context.Background()
+	c, err := Connect(ctx, NewHTTPTransport(newHTTPServer(t).URL, nil, nil), mcp.Implementation{Name: "test"})
+	testutil.AssertNoError(t, err)
+	defer c.Close()
+
+	local := mcp.NewMCPAdapter()
+	n, err := Federate(ctx, local, c, FederationOptions{Prefix: "remote", Exclude: []string{"fail"}, Scopes: []string{"remote:call"}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 1)
+
+	schema, ok := local.GetTool("remote.echo")
+	testutil.AssertEqual(t, ok, true)
+	testutil.AssertEqual(t, schema.Scopes, []string{"remote:call"})
+
+	got, err := local.CallTool(ctx, "remote.echo", map[string]interface{}{"n": float64(1)})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, got, map[string]interface{}{"n": float64(1)})
+
+	n, err = Federate(ctx, local, c, FederationOptions{Prefix: "again", Include: []string{"fail"}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, n, 1)
+	_, err = local.CallTool(ctx, "again.fail", nil)
+	testutil.AssertEqual(t, err != nil && strings.Contains(err.Error(), "boom"), true)
+}
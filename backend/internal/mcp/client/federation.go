@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// FederationOptions 联邦选项：Include 为空表示全部远程工具，Exclude 优先，均按远程工具名做 path.Match 通配
+type FederationOptions struct {
+	Prefix  string
+	Include []string
+	Exclude []string
+	Scopes  []string
+}
+
+// Federate 将远程服务的工具以 "<prefix>.<name>" 的名称重新注册到本地适配器，调用时转发给远程服务。
+// 返回注册的工具数量；名称不合法或已存在的工具会被跳过。
+func Federate(ctx context.Context, adapter mcp.MCPAdapter, c *Client, opts FederationOptions) (int, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	policy := mcp.ToolPolicy{Allow: opts.Include, Deny: opts.Exclude}
+	count := 0
+	for _, tool := range tools {
+		if !policy.Allowed(tool.Name) {
+			continue
+		}
+
+		name := tool.Name
+		if opts.Prefix != "" {
+			name = opts.Prefix + "." + tool.Name
+		}
+		schema := mcp.ToolSchema{
+			Name:         name,
+			Description:  tool.Description,
+			Parameters:   tool.InputSchema,
+			OutputSchema: tool.OutputSchema,
+			Annotations:  tool.Annotations,
+			Scopes:       opts.Scopes,
+		}
+		if err := adapter.RegisterTool(name, schema, proxyHandler(c, tool.Name)); err != nil {
+			log.Printf("Skip remote MCP tool %s: %v", name, err)
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// proxyHandler 转发调用到远程工具，远程 isError 结果转换为 error，其余结果原样透传
+func proxyHandler(c *Client, remoteName string) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		result, err := c.CallTool(ctx, remoteName, params)
+		if err != nil {
+			return nil, err
+		}
+
+		texts := make([]string, 0, len(result.Content))
+		for _, content := range result.Content {
+			if content.Type == mcp.ContentTypeText {
+				texts = append(texts, content.Text)
+			}
+		}
+		if result.IsError {
+			return nil, errors.New(strings.Join(texts, "\n"))
+		}
+
+		// 非结构化的单个文本块优先按 JSON 解析，避免结果被二次编码
+		if result.StructuredContent == nil && len(result.Content) == 1 && len(texts) == 1 {
+			var v interface{}
+			if err := json.Unmarshal([]byte(texts[0]), &v); err == nil {
+				return v, nil
+			}
+			return texts[0], nil
+		}
+		return result, nil
+	}
+}
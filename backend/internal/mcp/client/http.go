@@ -0,0 +1,215 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// SessionHeader Streamable HTTP 传输的会话头
+const SessionHeader = "Mcp-Session-Id"
+
+// ErrSessionExpired 服务端不再识别会话（返回 404），且无法重新握手
+var ErrSessionExpired = errors.New("mcp session expired")
+
+// HTTPTransport Streamable HTTP 传输：POST JSON-RPC 消息，响应可以是 JSON 或 SSE 流。
+// 会话过期（服务端重启或空闲超时）时重新发送 initialize 与 notifications/initialized 并重试一次
+type HTTPTransport struct {
+	url     string
+	headers http.Header
+	client  *http.Client
+
+	mu        sync.RWMutex
+	sessionID string
+	initReq   *mcp.JSONRPCRequest // 最近一次 initialize 请求，会话过期时重放
+
+	reinitMu sync.Mutex
+}
+
+// NewHTTPTransport 创建 HTTP 传输，headers 会附加到每个请求（如 X-API-Key、Authorization）
+func NewHTTPTransport(url string, headers map[string]string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	h := make(http.Header, len(headers))
+	for k, v := range headers {
+		h.Set(k, v)
+	}
+	return &HTTPTransport{url: url, headers: h, client: client}
+}
+
+// RoundTrip 发送一条消息，会话过期时重新握手后重试一次
+func (t *HTTPTransport) RoundTrip(ctx context.Context, req *mcp.JSONRPCRequest) (*Response, error) {
+	t.mu.Lock()
+	if req.Method == "initialize" {
+		t.initReq = req
+	}
+	sessionID := t.sessionID
+	t.mu.Unlock()
+
+	resp, err := t.send(ctx, req)
+	if !errors.Is(err, ErrSessionExpired) || req.Method == "initialize" {
+		return resp, err
+	}
+	if err := t.reinitialize(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	return t.send(ctx, req)
+}
+
+// reinitialize 清除过期的会话并重新握手；并发请求中只有第一个执行握手
+func (t *HTTPTransport) reinitialize(ctx context.Context, expired string) error {
+	t.reinitMu.Lock()
+	defer t.reinitMu.Unlock()
+
+	t.mu.Lock()
+	if t.sessionID != expired {
+		// 其他请求已完成重新握手
+		t.mu.Unlock()
+		return nil
+	}
+	t.sessionID = ""
+	initReq := t.initReq
+	t.mu.Unlock()
+	if initReq == nil {
+		return ErrSessionExpired
+	}
+
+	resp, err := t.send(ctx, initReq)
+	if err != nil {
+		return fmt.Errorf("reinitialize: %w", err)
+	}
+	if resp != nil && resp.Error != nil {
+		return fmt.Errorf("reinitialize: %w", resp.Error)
+	}
+	initialized := &mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPCVersion, Method: "notifications/initialized"}
+	if _, err := t.send(ctx, initialized); err != nil {
+		return fmt.Errorf("reinitialize: %w", err)
+	}
+	return nil
+}
+
+// send 发送一条消息；携带会话头却返回 404 时返回 ErrSessionExpired
+func (t *HTTPTransport) send(ctx context.Context, req *mcp.JSONRPCRequest) (*Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := t.newRequest(ctx, http.MethodPost, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+
+	httpResp, err := t.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode == http.StatusNotFound && httpReq.Header.Get(SessionHeader) != "" {
+		return nil, ErrSessionExpired
+	}
+
+	if id := httpResp.Header.Get(SessionHeader); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+
+	if httpResp.StatusCode == http.StatusAccepted || req.IsNotification() {
+		return nil, nil
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(httpResp.Body, 4096))
+		return nil, fmt.Errorf("unexpected status %d: %s", httpResp.StatusCode, strings.TrimSpace(string(data)))
+	}
+
+	if strings.HasPrefix(httpResp.Header.Get("Content-Type"), "text/event-stream") {
+		return readEventStream(httpResp.Body, req.ID)
+	}
+
+	var resp Response
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Close 结束远程会话
+func (t *HTTPTransport) Close() error {
+	t.mu.RLock()
+	id := t.sessionID
+	t.mu.RUnlock()
+	if id == "" {
+		return nil
+	}
+
+	req, err := t.newRequest(context.Background(), http.MethodDelete, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// newRequest 创建携带会话头与自定义头的请求
+func (t *HTTPTransport) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, t.url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range t.headers {
+		req.Header[k] = v
+	}
+	t.mu.RLock()
+	if t.sessionID != "" {
+		req.Header.Set(SessionHeader, t.sessionID)
+	}
+	t.mu.RUnlock()
+	return req, nil
+}
+
+// readEventStream 读取 SSE 流，跳过进度、日志等通知，返回与请求 ID 匹配的响应
+func readEventStream(r io.Reader, id json.RawMessage) (*Response, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "data:") {
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			continue
+		}
+		if line != "" || data.Len() == 0 {
+			continue
+		}
+
+		// 空行表示一个事件结束
+		var resp Response
+		if err := json.Unmarshal([]byte(data.String()), &resp); err == nil && bytes.Equal(resp.ID, id) {
+			return &resp, nil
+		}
+		data.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("event stream closed without response")
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+)
+
+// ErrTransportClosed 传输层已关闭
+var ErrTransportClosed = errors.New("mcp transport closed")
+
+// StdioTransport stdio 传输：启动子进程，通过 stdin/stdout 交换按行分隔的 JSON-RPC 消息
+type StdioTransport struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	pending map[string]chan *Response
+	done    chan struct{}
+	err     error
+}
+
+// NewStdioTransport 启动子进程，env 追加到当前进程的环境变量之后，子进程 stderr 透传到当前进程
+func NewStdioTransport(command string, args []string, env []string) (*StdioTransport, error) {
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", command, err)
+	}
+
+	t := &StdioTransport{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[string]chan *Response),
+		done:    make(chan struct{}),
+	}
+	go t.readLoop(stdout)
+	return t, nil
+}
+
+// RoundTrip 发送一条消息并等待对应 ID 的响应
+func (t *StdioTransport) RoundTrip(ctx context.Context, req *mcp.JSONRPCRequest) (*Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var ch chan *Response
+	if !req.IsNotification() {
+		ch = make(chan *Response, 1)
+		t.mu.Lock()
+		if t.err != nil {
+			t.mu.Unlock()
+			return nil, t.err
+		}
+		t.pending[string(req.ID)] = ch
+		t.mu.Unlock()
+		defer func() {
+			t.mu.Lock()
+			delete(t.pending, string(req.ID))
+			t.mu.Unlock()
+		}()
+	}
+
+	t.writeMu.Lock()
+	_, err = t.stdin.Write(append(data, '\n'))
+	t.writeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if ch == nil {
+		return nil, nil
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.done:
+		return nil, t.closedErr()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close 关闭 stdin 并等待子进程退出，超时后强制结束
+func (t *StdioTransport) Close() error {
+	t.stdin.Close()
+
+	select {
+	case <-t.done:
+	case <-time.After(5 * time.Second):
+		_ = t.cmd.Process.Kill()
+		<-t.done
+	}
+	if err := t.cmd.Wait(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+	}
+	return nil
+}
+
+// incomingMessage 子进程输出的消息：响应、通知或服务端发起的请求
+type incomingMessage struct {
+	Response
+	Method string `json:"method"`
+}
+
+// readLoop 读取子进程输出，将响应分发给等待中的请求，并答复服务端发起的请求
+func (t *StdioTransport) readLoop(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+
+	for scanner.Scan() {
+		var msg incomingMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			log.Printf("MCP stdio: invalid message: %v", err)
+			continue
+		}
+		// 服务端通知（无 ID）忽略；服务端请求的 ID 与客户端请求相互独立，不能当作响应
+		if msg.Method != "" {
+			if len(msg.ID) > 0 {
+				go t.reply(msg.ID, msg.Method)
+			}
+			continue
+		}
+		if len(msg.ID) == 0 || (msg.Result == nil && msg.Error == nil) {
+			continue
+		}
+
+		t.mu.Lock()
+		ch, ok := t.pending[string(msg.ID)]
+		t.mu.Unlock()
+		if ok {
+			ch <- &msg.Response
+		}
+	}
+
+	t.mu.Lock()
+	t.err = ErrTransportClosed
+	if err := scanner.Err(); err != nil {
+		t.err = fmt.Errorf("%w: %v", ErrTransportClosed, err)
+	}
+	t.mu.Unlock()
+	close(t.done)
+}
+
+// reply 答复服务端发起的请求：ping 返回空结果，其余方法返回 MethodNotFound
+func (t *StdioTransport) reply(id json.RawMessage, method string) {
+	resp := mcp.JSONRPCResponse{JSONRPC: mcp.JSONRPCVersion, ID: id}
+	if method == "ping" {
+		resp.Result = struct{}{}
+	} else {
+		resp.Error = mcp.NewJSONRPCError(mcp.CodeMethodNotFound, "method not found: "+method)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.stdin.Write(append(data, '\n')); err != nil {
+		log.Printf("MCP stdio: failed to reply to %s: %v", method, err)
+	}
+}
+
+// closedErr 传输层关闭原因
+func (t *StdioTransport) closedErr() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
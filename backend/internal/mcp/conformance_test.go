@@ -0,0 +1,35 @@
+package mcp_test
+
+import (
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+	"github.com/richer/ai_skeleton/internal/testutil"
+	"github.com/richer/ai_skeleton/internal/testutil/mcptest"
+)
+
+// newDefaultAdapter 注册项目内置的工具、资源与提示词
+func newDefaultAdapter(t *testing.T) mcp.MCPAdapter {
+	t.Helper()
+	a := mcp.NewMCPAdapter()
+	testutil.AssertNoError(t, mcp.RegisterAllTools(a))
+	testutil.AssertNoError(t, mcp.RegisterAllResources(a))
+	testutil.AssertNoError(t, mcp.RegisterAllPrompts(a))
+	return a
+}
+
+func TestConformance(t *testing.T) {
+	mcptest.RunConformance(t, func() mcp.MCPAdapter { return newDefaultAdapter(t) })
+}
+
+func TestToolsListGolden(t *testing.T) {
+	c := mcptest.NewClient(t, newDefaultAdapter(t))
+	c.AssertToolsGolden(t, "testdata/tools_list.golden")
+}
+
+func TestHealthCheckTool(t *testing.T) {
+	c := mcptest.NewClient(t, newDefaultAdapter(t))
+	result := c.CallTool(t, "health_check", nil)
+	testutil.AssertEqual(t, result.IsError, false)
+	testutil.AssertEqual(t, len(result.Content), 1)
+}
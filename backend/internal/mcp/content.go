@@ -0,0 +1,131 @@
+package mcp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 内容块类型
+const (
+	ContentTypeText         = "text"
+	ContentTypeImage        = "image"
+	ContentTypeResource     = "resource"
+	ContentTypeResourceLink = "resource_link"
+)
+
+// Content MCP 内容块（文本、图片、内嵌资源或资源链接）
+type Content struct {
+	Type        string            `json:"type"`
+	Text        string            `json:"text,omitempty"`
+	Data        string            `json:"data,omitempty"` // 图片的 base64 数据
+	MimeType    string            `json:"mimeType,omitempty"`
+	URI         string            `json:"uri,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Resource    *ResourceContents `json:"resource,omitempty"`
+}
+
+// TextContent 创建文本内容块
+func TextContent(text string) Content {
+	return Content{Type: ContentTypeText, Text: text}
+}
+
+// ImageContent 创建图片内容块，data 为原始字节
+func ImageContent(data []byte, mimeType string) Content {
+	return Content{Type: ContentTypeImage, Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// ResourceLinkContent 创建资源链接内容块，客户端可按需通过 resources/read 读取
+func ResourceLinkContent(resource Resource) Content {
+	return Content{
+		Type:        ContentTypeResourceLink,
+		URI:         resource.URI,
+		Name:        resource.Name,
+		Description: resource.Description,
+		MimeType:    resource.MimeType,
+	}
+}
+
+// EmbeddedResourceContent 创建内嵌资源内容块
+func EmbeddedResourceContent(contents ResourceContents) Content {
+	return Content{Type: ContentTypeResource, Resource: &contents}
+}
+
+// CallToolResult tools/call 的结果
+type CallToolResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// NewToolResult 由内容块创建工具结果，处理函数可直接返回
+func NewToolResult(content ...Content) *CallToolResult {
+	if content == nil {
+		content = []Content{}
+	}
+	return &CallToolResult{Content: content}
+}
+
+// StructuredResult 创建结构化结果：structuredContent 携带原值（须序列化为 JSON 对象），同时附带序列化后的文本以兼容旧客户端
+func StructuredResult(v interface{}) (*CallToolResult, error) {
+	text, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool result: %w", err)
+	}
+	if len(text) == 0 || text[0] != '{' {
+		return nil, fmt.Errorf("%w, got %s", ErrStructuredContent, jsonType(v))
+	}
+	return &CallToolResult{Content: []Content{TextContent(string(text))}, StructuredContent: v}, nil
+}
+
+// ErrorResult 创建工具执行失败的结果
+func ErrorResult(message string) *CallToolResult {
+	return &CallToolResult{Content: []Content{TextContent(message)}, IsError: true}
+}
+
+// WrapResult 将处理函数的返回值包装为工具结果：
+// 已是 CallToolResult 或内容块时原样使用，字符串作为文本，其他值序列化为 JSON 文本；
+// 工具声明了 OutputSchema 时同时填充 structuredContent，此时返回值须为对象（map 或结构体），否则返回 ErrStructuredContent。
+func WrapResult(v interface{}, schema ToolSchema) (*CallToolResult, error) {
+	switch val := v.(type) {
+	case *CallToolResult:
+		if val == nil {
+			return NewToolResult(), nil
+		}
+		return val, nil
+	case CallToolResult:
+		return &val, nil
+	case Content:
+		return NewToolResult(val), nil
+	case []Content:
+		return NewToolResult(val...), nil
+	case nil:
+		return NewToolResult(), nil
+	case string:
+		if schema.OutputSchema == nil {
+			return NewToolResult(TextContent(val)), nil
+		}
+	}
+
+	if schema.OutputSchema != nil {
+		return StructuredResult(v)
+	}
+	text, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool result: %w", err)
+	}
+	return NewToolResult(TextContent(string(text))), nil
+}
+
+// contentText 拼接内容块中的文本
+func contentText(content []Content) string {
+	texts := make([]string, 0, len(content))
+	for _, c := range content {
+		if c.Type == ContentTypeText {
+			texts = append(texts, c.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
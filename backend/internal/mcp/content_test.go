@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestWrapResult(t *testing.T) {
+	outputSchema := map[string]interface{}{"type": "object"}
+	image := ImageContent([]byte("png"), "image/png")
+
+	tests := []struct {
+		name   string
+		value  interface{}
+		schema ToolSchema
+		want   *CallToolResult
+	}{
+		{
+			name:  "nil 返回空内容",
+			value: nil,
+			want:  &CallToolResult{Content: []Content{}},
+		},
+		{
+			name:  "字符串作为文本",
+			value: "ok",
+			want:  &CallToolResult{Content: []Content{TextContent("ok")}},
+		},
+		{
+			name:  "普通值序列化为 JSON 文本",
+			value: map[string]int{"n": 1},
+			want:  &CallToolResult{Content: []Content{TextContent(`{"n":1}`)}},
+		},
+		{
+			name:   "声明 OutputSchema 时携带结构化结果",
+			value:  map[string]int{"n": 1},
+			schema: ToolSchema{OutputSchema: outputSchema},
+			want:   &CallToolResult{Content: []Content{TextContent(`{"n":1}`)}, StructuredContent: map[string]int{"n": 1}},
+		},
+		{
+			name:  "单个内容块",
+			value: image,
+			want:  &CallToolResult{Content: []Content{{Type: ContentTypeImage, Data: "cG5n", MimeType: "image/png"}}},
+		},
+		{
+			name:  "多个内容块",
+			value: []Content{TextContent("see"), ResourceLinkContent(Resource{URI: "file:///a.txt", Name: "a"})},
+			want: &CallToolResult{Content: []Content{
+				TextContent("see"),
+				{Type: ContentTypeResourceLink, URI: "file:///a.txt", Name: "a"},
+			}},
+		},
+		{
+			name:  "CallToolResult 原样返回",
+			value: ErrorResult("bad"),
+			want:  &CallToolResult{Content: []Content{TextContent("bad")}, IsError: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WrapResult(tt.value, tt.schema)
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestWrapResult_StructuredContentMustBeObject(t *testing.T) {
+	schema := ToolSchema{OutputSchema: map[string]interface{}{"type": "object"}}
+	for _, v := range []interface{}{[]map[string]int{{"n": 1}}, "ok", 42} {
+		_, err := WrapResult(v, schema)
+		testutil.AssertEqual(t, errors.Is(err, ErrStructuredContent), true)
+	}
+
+	type user struct {
+		Name string `json:"name"`
+	}
+	got, err := WrapResult(user{Name: "bob"}, schema)
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, got.StructuredContent, interface{}(user{Name: "bob"}))
+
+	err = NewMCPAdapter().RegisterTool("list", ToolSchema{OutputSchema: map[string]interface{}{"type": "array"}}, echoHandler)
+	testutil.AssertEqual(t, errors.Is(err, ErrInvalidOutputSchema), true)
+}
+
+func TestMCPAdapter_HandleRequest_ToolResult(t *testing.T) {
+	a := NewMCPAdapter()
+	results := map[string]*CallToolResult{
+		"structured": {Content: []Content{TextContent(`{"n":1}`)}, StructuredContent: map[string]interface{}{"n": 1}},
+		"content":    NewToolResult(EmbeddedResourceContent(ResourceContents{URI: "mem://x", Text: "x"})),
+		"failed":     ErrorResult("bad input"),
+	}
+	for name, result := range results {
+		result := result
+		testutil.AssertNoError(t, a.RegisterTool(name, ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return result, nil
+		}))
+	}
+
+	resp, _ := a.HandleRequest(context.Background(), &MCPRequest{Tool: "structured"})
+	testutil.AssertEqual(t, resp.Data, map[string]interface{}{"n": 1})
+
+	resp, _ = a.HandleRequest(context.Background(), &MCPRequest{Tool: "content"})
+	testutil.AssertEqual(t, resp.Data, results["content"].Content)
+
+	resp, _ = a.HandleRequest(context.Background(), &MCPRequest{Tool: "failed"})
+	testutil.AssertEqual(t, resp.Success, false)
+	testutil.AssertEqual(t, resp.Error, "bad input")
+}
@@ -0,0 +1,24 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MCP 注册与调用错误定义
+var (
+	ErrToolNotFound     = errors.New("tool not found")
+	ErrToolExists       = errors.New("tool already registered")
+	ErrInvalidToolName  = errors.New("invalid tool name")
+	ErrToolNameMismatch = errors.New("tool name does not match schema name")
+	ErrNilHandler       = errors.New("tool handler is nil")
+	ErrInvalidParams    = errors.New("invalid tool params")
+
+	// ErrInvalidOutputSchema OutputSchema 的 type 必须为 object（structuredContent 只能是 JSON 对象）
+	ErrInvalidOutputSchema = errors.New("output schema must be of type object")
+	// ErrStructuredContent 声明了 OutputSchema 的工具返回了非对象的值
+	ErrStructuredContent = errors.New("structured content must be a JSON object")
+
+	// ErrInvalidArguments 参数与工具的 inputSchema 不匹配，在调用处理函数之前返回（同时匹配 ErrInvalidParams）
+	ErrInvalidArguments = fmt.Errorf("%w: arguments do not match input schema", ErrInvalidParams)
+)
@@ -0,0 +1,187 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Interceptor 工具调用拦截器，用于实现鉴权、日志、超时、恢复等横切逻辑
+type Interceptor func(next ToolHandler) ToolHandler
+
+// CallInfo 当前工具调用信息，由适配器在调用拦截器链前写入 context
+type CallInfo struct {
+	Tool   string
+	Schema ToolSchema
+}
+
+type callInfoKey struct{}
+
+// withCallInfo 写入调用信息
+func withCallInfo(ctx context.Context, info CallInfo) context.Context {
+	return context.WithValue(ctx, callInfoKey{}, info)
+}
+
+// CallInfoFromContext 读取当前工具调用信息
+func CallInfoFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callInfoKey{}).(CallInfo)
+	return info, ok
+}
+
+// 拦截器错误定义
+var (
+	ErrToolPanic   = errors.New("tool panicked")
+	ErrToolTimeout = errors.New("tool execution timed out")
+)
+
+// DefaultRedactFields 默认脱敏的参数字段（不区分大小写）
+var DefaultRedactFields = []string{"password", "token", "secret", "api_key", "apikey", "authorization"}
+
+// redactedValue 脱敏后的占位值
+const redactedValue = "***"
+
+// Recovery 捕获工具处理函数中的 panic 并转换为错误
+func Recovery() Interceptor {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (result interface{}, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					info, _ := CallInfoFromContext(ctx)
+					slog.Error("mcp tool panicked", "tool", info.Tool, "panic", r, "stack", string(debug.Stack()))
+					result, err = nil, fmt.Errorf("%w: %s: %v", ErrToolPanic, info.Tool, r)
+				}
+			}()
+			return next(ctx, params)
+		}
+	}
+}
+
+// Timeout 限制工具执行时间，perTool 中的配置优先于默认值，值 <= 0 表示不限制
+func Timeout(defaultTimeout time.Duration, perTool map[string]time.Duration) Interceptor {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			info, _ := CallInfoFromContext(ctx)
+			timeout := defaultTimeout
+			if d, ok := perTool[info.Tool]; ok {
+				timeout = d
+			}
+			if timeout <= 0 {
+				return next(ctx, params)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			type outcome struct {
+				result interface{}
+				err    error
+				panic  interface{}
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				var out outcome
+				defer func() {
+					// 将 panic 交还给调用方 goroutine，由外层 Recovery 处理
+					if r := recover(); r != nil {
+						out.panic = r
+					}
+					done <- out
+				}()
+				out.result, out.err = next(ctx, params)
+			}()
+
+			select {
+			case out := <-done:
+				if out.panic != nil {
+					panic(out.panic)
+				}
+				return out.result, out.err
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return nil, fmt.Errorf("%w: %s after %s", ErrToolTimeout, info.Tool, timeout)
+				}
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+// Logging 以结构化日志记录每次工具调用，参数按 redactFields 脱敏（为空时使用 DefaultRedactFields）
+func Logging(logger *slog.Logger, redactFields ...string) Interceptor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if len(redactFields) == 0 {
+		redactFields = DefaultRedactFields
+	}
+
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			info, _ := CallInfoFromContext(ctx)
+			start := time.Now()
+
+			result, err := next(ctx, params)
+
+			attrs := []any{
+				"tool", info.Tool,
+				"args", RedactArgs(params, redactFields...),
+				"duration", time.Since(start),
+			}
+			if err != nil {
+				logger.WarnContext(ctx, "mcp tool call failed", append(attrs, "error", err)...)
+			} else {
+				logger.InfoContext(ctx, "mcp tool call", attrs...)
+			}
+			return result, err
+		}
+	}
+}
+
+// RedactArgs 返回参数副本，敏感字段（含嵌套对象与数组）的值替换为 "***"
+func RedactArgs(params map[string]interface{}, fields ...string) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+
+	sensitive := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		sensitive[strings.ToLower(f)] = true
+	}
+	return redactMap(params, sensitive)
+}
+
+// redactMap 递归脱敏对象
+func redactMap(m map[string]interface{}, sensitive map[string]bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if sensitive[strings.ToLower(k)] {
+			out[k] = redactedValue
+			continue
+		}
+		out[k] = redactValue(v, sensitive)
+	}
+	return out
+}
+
+// redactValue 递归脱敏任意值
+func redactValue(v interface{}, sensitive map[string]bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return redactMap(val, sensitive)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactValue(item, sensitive)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONRPCVersion JSON-RPC 协议版本
+const JSONRPCVersion = "2.0"
+
+// JSON-RPC 标准错误码与 MCP 扩展错误码
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeResourceNotFound = -32002
+)
+
+// JSONRPCRequest JSON-RPC 请求或通知（通知不带 ID）
+type JSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification 是否为通知（无需响应）
+func (r *JSONRPCRequest) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+// JSONRPCResponse JSON-RPC 响应
+type JSONRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *JSONRPCError   `json:"error,omitempty"`
+}
+
+// JSONRPCNotification 服务端发往客户端的 JSON-RPC 通知
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// JSONRPCError JSON-RPC 错误对象
+type JSONRPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *JSONRPCError) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+// NewJSONRPCError 创建 JSON-RPC 错误
+func NewJSONRPCError(code int, message string) *JSONRPCError {
+	return &JSONRPCError{Code: code, Message: message}
+}
+
+// newResult 创建成功响应
+func newResult(id json.RawMessage, result interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{JSONRPC: JSONRPCVersion, ID: id, Result: result}
+}
+
+// newErrorResponse 创建错误响应，无法解析 ID 时使用 null
+func newErrorResponse(id json.RawMessage, err *JSONRPCError) *JSONRPCResponse {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &JSONRPCResponse{JSONRPC: JSONRPCVersion, ID: id, Error: err}
+}
+
+// ParseErrorResponse 创建解析失败响应，供传输层在无法解析请求体时使用
+func ParseErrorResponse(err error) *JSONRPCResponse {
+	return newErrorResponse(nil, NewJSONRPCError(CodeParseError, "parse error: "+err.Error()))
+}
+
+// InvalidRequestResponse 创建非法请求响应，供传输层在请求结构不合法时使用（如空批量）
+func InvalidRequestResponse(message string) *JSONRPCResponse {
+	return newErrorResponse(nil, NewJSONRPCError(CodeInvalidRequest, message))
+}
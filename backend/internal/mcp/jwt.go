@@ -0,0 +1,172 @@
+package mcp
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// JWTClaims Bearer Token 中使用的声明
+type JWTClaims struct {
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss"`
+	Audience  interface{} `json:"aud"`
+	ExpiresAt int64       `json:"exp"`
+	NotBefore int64       `json:"nbf"`
+	Scope     string      `json:"scope"`
+	Scp       []string    `json:"scp"`
+}
+
+// Scopes 返回权限列表，兼容 OAuth 的 scope（空格分隔）与 scp（数组）两种写法
+func (c *JWTClaims) Scopes() []string {
+	scopes := strings.Fields(c.Scope)
+	return append(scopes, c.Scp...)
+}
+
+// hasAudience 是否包含指定 audience
+func (c *JWTClaims) hasAudience(aud string) bool {
+	switch v := c.Audience.(type) {
+	case string:
+		return v == aud
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JWTVerifier JWT 校验器，支持 HS256（共享密钥）与 RS256（公钥），仅接受配置的算法
+type JWTVerifier struct {
+	secret    []byte
+	publicKey *rsa.PublicKey
+	issuer    string
+	audience  string
+	now       func() time.Time
+}
+
+// errMissingAudience 未配置 audience（否则同一签发方为其他服务签发的令牌也会被接受）
+var errMissingAudience = errors.New("jwt audience is required")
+
+// NewHS256Verifier 创建 HS256 校验器，audience 不能为空
+func NewHS256Verifier(secret, issuer, audience string) (*JWTVerifier, error) {
+	if audience == "" {
+		return nil, errMissingAudience
+	}
+	return &JWTVerifier{secret: []byte(secret), issuer: issuer, audience: audience, now: time.Now}, nil
+}
+
+// NewRS256Verifier 从 PEM 公钥文件创建 RS256 校验器，audience 不能为空
+func NewRS256Verifier(publicKeyFile, issuer, audience string) (*JWTVerifier, error) {
+	if audience == "" {
+		return nil, errMissingAudience
+	}
+	data, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("invalid public key: no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid public key: not an RSA key")
+	}
+	return &JWTVerifier{publicKey: rsaKey, issuer: issuer, audience: audience, now: time.Now}, nil
+}
+
+// Verify 校验签名、有效期（必须包含 exp）、issuer 与 audience
+func (v *JWTVerifier) Verify(token string) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if err := v.verifySignature(header.Alg, signed, sig); err != nil {
+		return nil, err
+	}
+
+	var claims JWTClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	now := v.now().Unix()
+	if claims.ExpiresAt == 0 {
+		return nil, fmt.Errorf("%w: missing exp claim", ErrInvalidToken)
+	}
+	if now >= claims.ExpiresAt {
+		return nil, fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if !claims.hasAudience(v.audience) {
+		return nil, fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// verifySignature 按配置的密钥类型校验签名，防止算法混淆
+func (v *JWTVerifier) verifySignature(alg string, signed, sig []byte) error {
+	switch {
+	case alg == "HS256" && len(v.secret) > 0:
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write(signed)
+		if !hmac.Equal(mac.Sum(nil), sig) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	case alg == "RS256" && v.publicKey != nil:
+		digest := sha256.Sum256(signed)
+		if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+}
+
+// decodeSegment 解码 base64url JSON 片段
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	return nil
+}
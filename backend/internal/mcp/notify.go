@@ -0,0 +1,57 @@
+package mcp
+
+import "sync"
+
+// MCP 服务端通知方法
+const (
+	MethodToolsListChanged = "notifications/tools/list_changed"
+)
+
+// Notification MCP 服务端通知
+type Notification struct {
+	Method string                 `json:"method"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+// NotificationHandler 通知处理函数
+type NotificationHandler func(n Notification)
+
+// notifier 通知订阅者管理
+type notifier struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]NotificationHandler
+}
+
+// subscribe 添加订阅者，返回取消订阅函数
+func (n *notifier) subscribe(handler NotificationHandler) func() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.handlers == nil {
+		n.handlers = make(map[int]NotificationHandler)
+	}
+	id := n.nextID
+	n.nextID++
+	n.handlers[id] = handler
+
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.handlers, id)
+	}
+}
+
+// notify 向所有订阅者发送通知（同步调用，调用方不能持有注册表锁）
+func (n *notifier) notify(notification Notification) {
+	n.mu.RLock()
+	handlers := make([]NotificationHandler, 0, len(n.handlers))
+	for _, h := range n.handlers {
+		handlers = append(handlers, h)
+	}
+	n.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(notification)
+	}
+}
@@ -0,0 +1,434 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// OpenAPISpec swag 生成的 Swagger 2.0 文档（docs/swagger.json）中桥接所需的部分
+type OpenAPISpec struct {
+	BasePath    string                                `json:"basePath"`
+	Paths       map[string]map[string]json.RawMessage `json:"paths"` // 路径项：HTTP 方法对应的接口，以及 parameters、summary 等路径级字段
+	Definitions map[string]map[string]interface{}     `json:"definitions"`
+}
+
+// OpenAPIOperation 单个接口（对应一组 @Summary/@Param/@Router 注解）
+type OpenAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Summary     string             `json:"summary"`
+	Description string             `json:"description"`
+	Tags        []string           `json:"tags"`
+	Parameters  []OpenAPIParameter `json:"parameters"`
+}
+
+// OpenAPIParameter 接口参数（对应 @Param 注解）
+type OpenAPIParameter struct {
+	Name        string                 `json:"name"`
+	In          string                 `json:"in"`
+	Description string                 `json:"description"`
+	Required    bool                   `json:"required"`
+	Type        string                 `json:"type"`
+	Items       map[string]interface{} `json:"items"`
+	Enum        []interface{}          `json:"enum"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+// OpenAPIToolOptions 桥接选项：Include 为空表示全部接口，Exclude 优先，均按工具名做 path.Match 通配
+type OpenAPIToolOptions struct {
+	Include     []string
+	Exclude     []string
+	ExcludeTags []string
+	Scopes      []string
+}
+
+// LoadOpenAPISpec 读取 swag 生成的 swagger.json
+func LoadOpenAPISpec(file string) (*OpenAPISpec, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read openapi spec: %w", err)
+	}
+	var spec OpenAPISpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse openapi spec %s: %w", file, err)
+	}
+	return &spec, nil
+}
+
+// RegisterOpenAPITools 将文档中选中的接口注册为 MCP 工具，调用时通过 handler（Gin 引擎）在进程内分发
+func RegisterOpenAPITools(adapter MCPAdapter, spec *OpenAPISpec, handler http.Handler, opts OpenAPIToolOptions) error {
+	policy := ToolPolicy{Allow: opts.Include, Deny: opts.Exclude}
+
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	count := 0
+	for _, p := range paths {
+		ops, err := spec.operations(p)
+		if err != nil {
+			return err
+		}
+		for _, o := range ops {
+			method, op := o.method, o.op
+			if hasAnyTag(op.Tags, opts.ExcludeTags) {
+				continue
+			}
+
+			name := openAPIToolName(method, p, op)
+			if !policy.Allowed(name) {
+				continue
+			}
+
+			route := openAPIRoute{method: method, path: joinBasePath(spec.BasePath, p), params: op.Parameters}
+			schema := ToolSchema{
+				Name:        name,
+				Description: openAPIDescription(method, p, op),
+				Parameters:  spec.inputSchema(op.Parameters),
+				Annotations: openAPIAnnotations(method, op),
+				Scopes:      opts.Scopes,
+			}
+			if err := adapter.RegisterTool(name, schema, route.handler(handler)); err != nil {
+				return fmt.Errorf("failed to register openapi tool %s: %w", name, err)
+			}
+			count++
+		}
+	}
+
+	log.Printf("Registered %d MCP tools from OpenAPI spec", count)
+	return nil
+}
+
+// openAPIMethod 路径项中的一个接口
+type openAPIMethod struct {
+	method string
+	op     OpenAPIOperation
+}
+
+// operations 解析路径项中的接口，按方法名排序；跳过 parameters、summary、servers 等非 HTTP 方法字段，
+// 路径级 parameters 合并到每个接口中（同名同位置的参数以接口声明为准）
+func (s *OpenAPISpec) operations(p string) ([]openAPIMethod, error) {
+	item := s.Paths[p]
+
+	var shared []OpenAPIParameter
+	if raw, ok := item["parameters"]; ok {
+		if err := json.Unmarshal(raw, &shared); err != nil {
+			return nil, fmt.Errorf("failed to parse parameters of openapi path %s: %w", p, err)
+		}
+	}
+
+	var ops []openAPIMethod
+	for key, raw := range item {
+		method := strings.ToUpper(key)
+		if !isHTTPMethod(method) {
+			continue
+		}
+		var op OpenAPIOperation
+		if err := json.Unmarshal(raw, &op); err != nil {
+			return nil, fmt.Errorf("failed to parse openapi operation %s %s: %w", method, p, err)
+		}
+		op.Parameters = mergeParameters(shared, op.Parameters)
+		ops = append(ops, openAPIMethod{method: method, op: op})
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].method < ops[j].method })
+	return ops, nil
+}
+
+// mergeParameters 合并路径级与接口级参数，接口级参数覆盖同名同位置的路径级参数
+func mergeParameters(shared, own []OpenAPIParameter) []OpenAPIParameter {
+	if len(shared) == 0 {
+		return own
+	}
+	declared := make(map[string]bool, len(own))
+	for _, param := range own {
+		declared[param.In+":"+param.Name] = true
+	}
+	merged := make([]OpenAPIParameter, 0, len(shared)+len(own))
+	for _, param := range shared {
+		if !declared[param.In+":"+param.Name] {
+			merged = append(merged, param)
+		}
+	}
+	return append(merged, own...)
+}
+
+// openAPIRoute 工具到 REST 接口的映射
+type openAPIRoute struct {
+	method string
+	path   string
+	params []OpenAPIParameter
+}
+
+// handler 将工具参数映射为 HTTP 请求并在进程内执行
+func (r openAPIRoute) handler(h http.Handler) ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		req, err := r.request(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		body := rec.Body.Bytes()
+		if rec.Code >= http.StatusBadRequest {
+			return nil, fmt.Errorf("%s %s returned %d: %s", r.method, req.URL.Path, rec.Code, strings.TrimSpace(string(body)))
+		}
+
+		var result interface{}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return string(body), nil
+		}
+		return result, nil
+	}
+}
+
+// request 按参数位置（path/query/header/body/formData）构造请求
+func (r openAPIRoute) request(ctx context.Context, params map[string]interface{}) (*http.Request, error) {
+	p := r.path
+	query := url.Values{}
+	form := url.Values{}
+	header := http.Header{}
+	var body io.Reader
+
+	for _, param := range r.params {
+		v, ok := params[param.Name]
+		if !ok || v == nil {
+			if param.Required {
+				return nil, fmt.Errorf("%w: missing required parameter %q", ErrInvalidParams, param.Name)
+			}
+			continue
+		}
+
+		switch param.In {
+		case "path":
+			p = strings.ReplaceAll(p, "{"+param.Name+"}", url.PathEscape(paramString(v)))
+		case "query":
+			if list, ok := v.([]interface{}); ok {
+				for _, item := range list {
+					query.Add(param.Name, paramString(item))
+				}
+				continue
+			}
+			query.Set(param.Name, paramString(v))
+		case "header":
+			header.Set(param.Name, paramString(v))
+		case "formData":
+			form.Set(param.Name, paramString(v))
+		case "body":
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidParams, err)
+			}
+			body = bytes.NewReader(data)
+			header.Set("Content-Type", "application/json")
+		}
+	}
+
+	if body == nil && len(form) > 0 {
+		body = strings.NewReader(form.Encode())
+		header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	target := p
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, r.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// inputSchema 将接口参数转换为工具的 JSON Schema
+func (s *OpenAPISpec) inputSchema(params []OpenAPIParameter) map[string]interface{} {
+	properties := make(map[string]interface{}, len(params))
+	required := []string{}
+
+	for _, param := range params {
+		var prop map[string]interface{}
+		if param.In == "body" && param.Schema != nil {
+			prop = s.resolve(param.Schema, map[string]bool{})
+		} else {
+			prop = map[string]interface{}{"type": param.Type}
+			if param.Items != nil {
+				prop["items"] = param.Items
+			}
+			if len(param.Enum) > 0 {
+				prop["enum"] = param.Enum
+			}
+		}
+		if param.Description != "" {
+			prop["description"] = param.Description
+		}
+		properties[param.Name] = prop
+		if param.Required {
+			required = append(required, param.Name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// resolve 展开 #/definitions 引用（递归引用保留为 object）
+func (s *OpenAPISpec) resolve(schema map[string]interface{}, seen map[string]bool) map[string]interface{} {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		def, ok := s.Definitions[name]
+		if !ok || seen[name] {
+			return map[string]interface{}{"type": "object"}
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		return s.resolve(def, seen)
+	}
+
+	out := make(map[string]interface{}, len(schema))
+	for k, v := range schema {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			if k == "properties" {
+				props := make(map[string]interface{}, len(val))
+				for name, prop := range val {
+					if m, ok := prop.(map[string]interface{}); ok {
+						props[name] = s.resolve(m, seen)
+					} else {
+						props[name] = prop
+					}
+				}
+				out[k] = props
+				continue
+			}
+			out[k] = s.resolve(val, seen)
+		case []interface{}:
+			// allOf 等组合：逐项展开
+			items := make([]interface{}, len(val))
+			for i, item := range val {
+				if m, ok := item.(map[string]interface{}); ok {
+					items[i] = s.resolve(m, seen)
+				} else {
+					items[i] = item
+				}
+			}
+			out[k] = items
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
+
+var nonToolNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// openAPIToolName 工具名：优先使用 operationId，否则由方法和路径生成（如 get_api_v1_health）；
+// 超过 128 个字符时截断并附加方法与路径的短哈希，避免前缀相同的接口截断后重名
+func openAPIToolName(method, p string, op OpenAPIOperation) string {
+	name := strings.ToLower(method) + "_" + strings.Trim(nonToolNameChars.ReplaceAllString(p, "_"), "_")
+	if op.OperationID != "" {
+		name = nonToolNameChars.ReplaceAllString(op.OperationID, "_")
+	}
+	if len(name) > maxToolNameLen {
+		sum := sha1.Sum([]byte(strings.ToUpper(method) + " " + p))
+		suffix := "_" + hex.EncodeToString(sum[:])[:8]
+		name = name[:maxToolNameLen-len(suffix)] + suffix
+	}
+	return name
+}
+
+// openAPIDescription 工具描述：摘要与说明，末尾附带对应的接口
+func openAPIDescription(method, p string, op OpenAPIOperation) string {
+	parts := make([]string, 0, 3)
+	if op.Summary != "" {
+		parts = append(parts, op.Summary)
+	}
+	if op.Description != "" && op.Description != op.Summary {
+		parts = append(parts, op.Description)
+	}
+	parts = append(parts, fmt.Sprintf("(%s %s)", method, p))
+	return strings.Join(parts, " ")
+}
+
+// openAPIAnnotations 按 HTTP 方法语义推断工具行为提示
+func openAPIAnnotations(method string, op OpenAPIOperation) *ToolAnnotations {
+	annotations := &ToolAnnotations{Title: op.Summary}
+	switch method {
+	case http.MethodGet:
+		annotations.ReadOnlyHint = Hint(true)
+		annotations.IdempotentHint = Hint(true)
+	case http.MethodPut:
+		annotations.DestructiveHint = Hint(false)
+		annotations.IdempotentHint = Hint(true)
+	case http.MethodDelete:
+		annotations.DestructiveHint = Hint(true)
+		annotations.IdempotentHint = Hint(true)
+	}
+	return annotations
+}
+
+// joinBasePath 拼接 basePath（路径已包含 basePath 时不重复拼接）
+func joinBasePath(base, p string) string {
+	base = strings.TrimRight(base, "/")
+	if base == "" || p == base || strings.HasPrefix(p, base+"/") {
+		return p
+	}
+	return base + p
+}
+
+// isHTTPMethod 过滤 paths 下的非方法字段（如 parameters）
+func isHTTPMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// hasAnyTag 接口是否带有任一标签
+func hasAnyTag(tags, targets []string) bool {
+	for _, tag := range tags {
+		for _, target := range targets {
+			if tag == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// paramString 参数值转字符串（JSON 数字解码为 float64，整数去掉小数部分）
+func paramString(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		if val == float64(int64(val)) {
+			return fmt.Sprintf("%d", int64(val))
+		}
+		return fmt.Sprintf("%v", val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+const testSwagger = `{
+  "basePath": "/api/v1",
+  "paths": {
+    "/api/v1/health": {
+      "get": {"summary": "健康检查", "tags": ["系统"], "parameters": []}
+    },
+    "/users/{id}": {
+      "put": {
+        "operationId": "updateUser",
+        "summary": "更新用户",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true, "type": "integer"},
+          {"name": "notify", "in": "query", "type": "boolean"},
+          {"name": "user", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}}
+        ]
+      }
+    },
+    "/api/v1/mcp/tools": {
+      "get": {"summary": "列出工具", "tags": ["MCP"]}
+    }
+  },
+  "definitions": {
+    "User": {"type": "object", "properties": {"name": {"type": "string"}, "friend": {"$ref": "#/definitions/User"}}}
+  }
+}`
+
+func loadTestSpec(t *testing.T) *OpenAPISpec {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "swagger.json")
+	testutil.AssertNoError(t, os.WriteFile(file, []byte(testSwagger), 0o644))
+	spec, err := LoadOpenAPISpec(file)
+	testutil.AssertNoError(t, err)
+	return spec
+}
+
+func TestRegisterOpenAPITools(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":200,"message":"success"}`))
+	})
+	mux.HandleFunc("PUT /api/v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]string{
+			"id":     r.PathValue("id"),
+			"notify": r.URL.Query().Get("notify"),
+			"body":   string(body),
+		})
+	})
+
+	a := NewMCPAdapter()
+	err := RegisterOpenAPITools(a, loadTestSpec(t), mux, OpenAPIToolOptions{ExcludeTags: []string{"MCP"}})
+	testutil.AssertNoError(t, err)
+
+	names := []string{}
+	for _, tool := range a.ListTools() {
+		names = append(names, tool.Name)
+	}
+	testutil.AssertEqual(t, names, []string{"get_api_v1_health", "updateUser"})
+
+	schema, _ := a.GetTool("updateUser")
+	testutil.AssertEqual(t, schema.Parameters["required"], []string{"id", "user"})
+	user := schema.Parameters["properties"].(map[string]interface{})["user"].(map[string]interface{})
+	friend := user["properties"].(map[string]interface{})["friend"]
+	testutil.AssertEqual(t, friend, map[string]interface{}{"type": "object"})
+
+	tests := []struct {
+		name    string
+		tool    string
+		params  map[string]interface{}
+		want    interface{}
+		wantErr error
+	}{
+		{
+			name: "无参数接口",
+			tool: "get_api_v1_health",
+			want: map[string]interface{}{"code": float64(200), "message": "success"},
+		},
+		{
+			name:   "路径、查询与请求体参数",
+			tool:   "updateUser",
+			params: map[string]interface{}{"id": float64(7), "notify": true, "user": map[string]interface{}{"name": "bob"}},
+			want:   map[string]interface{}{"id": "7", "notify": "true", "body": `{"name":"bob"}`},
+		},
+		{
+			name:    "缺少必填参数",
+			tool:    "updateUser",
+			params:  map[string]interface{}{"id": float64(7)},
+			wantErr: ErrInvalidParams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.CallTool(context.Background(), tt.tool, tt.params)
+			if tt.wantErr != nil {
+				testutil.AssertEqual(t, errors.Is(err, tt.wantErr), true)
+				return
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestRegisterOpenAPITools_Include(t *testing.T) {
+	a := NewMCPAdapter()
+	err := RegisterOpenAPITools(a, loadTestSpec(t), http.NotFoundHandler(), OpenAPIToolOptions{Include: []string{"get_*"}})
+	testutil.AssertNoError(t, err)
+	testutil.AssertEqual(t, len(a.ListTools()), 2)
+
+	_, err = a.CallTool(context.Background(), "get_api_v1_health", nil)
+	testutil.AssertError(t, err)
+}
+
+func TestRegisterOpenAPITools_PathItem(t *testing.T) {
+	longID := strings.Repeat("a", 200)
+	spec := &OpenAPISpec{}
+	testutil.AssertNoError(t, json.Unmarshal([]byte(`{
+  "paths": {
+    "/orders/{id}": {
+      "summary": "订单",
+      "servers": [{"url": "https://example.com"}],
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "type": "integer"},
+        {"name": "verbose", "in": "query", "type": "boolean"}
+      ],
+      "get": {"operationId": "getOrder"},
+      "delete": {
+        "operationId": "`+longID+`",
+        "parameters": [{"name": "verbose", "in": "query", "type": "string", "required": true}]
+      }
+    }
+  }
+}`), spec))
+
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, RegisterOpenAPITools(a, spec, http.NotFoundHandler(), OpenAPIToolOptions{}))
+
+	names := []string{}
+	for _, tool := range a.ListTools() {
+		names = append(names, tool.Name)
+	}
+	longName := openAPIToolName("delete", "/orders/{id}", OpenAPIOperation{OperationID: longID})
+	testutil.AssertEqual(t, len(longName), 128)
+	testutil.AssertEqual(t, strings.HasPrefix(longName, longID[:119]+"_"), true)
+	testutil.AssertEqual(t, names, []string{longName, "getOrder"})
+
+	// 路径级参数合并到每个接口，接口级同名参数优先
+	get, _ := a.GetTool("getOrder")
+	testutil.AssertEqual(t, get.Parameters["required"], []string{"id"})
+	del, _ := a.GetTool(longName)
+	testutil.AssertEqual(t, del.Parameters["required"], []string{"id", "verbose"})
+	verbose := del.Parameters["properties"].(map[string]interface{})["verbose"].(map[string]interface{})
+	testutil.AssertEqual(t, verbose["type"], "string")
+}
+
+func TestRegisterOpenAPITools_LongPaths(t *testing.T) {
+	prefix := "/" + strings.Repeat("segment/", 20)
+	spec := &OpenAPISpec{Paths: map[string]map[string]json.RawMessage{
+		prefix + "first":  {"get": json.RawMessage(`{}`)},
+		prefix + "second": {"get": json.RawMessage(`{}`)},
+	}}
+
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, RegisterOpenAPITools(a, spec, http.NotFoundHandler(), OpenAPIToolOptions{}))
+
+	tools := a.ListTools()
+	testutil.AssertEqual(t, len(tools), 2)
+	for _, tool := range tools {
+		testutil.AssertEqual(t, len(tool.Name), 128)
+	}
+	if tools[0].Name == tools[1].Name {
+		t.Fatalf("truncated tool names collide: %s", tools[0].Name)
+	}
+}
+
+func TestOpenAPISpec_Operations(t *testing.T) {
+	spec := &OpenAPISpec{Paths: map[string]map[string]json.RawMessage{
+		"/a": {
+			"put":        json.RawMessage(`{}`),
+			"get":        json.RawMessage(`{}`),
+			"delete":     json.RawMessage(`{}`),
+			"x-internal": json.RawMessage(`true`),
+		},
+	}}
+	ops, err := spec.operations("/a")
+	testutil.AssertNoError(t, err)
+	methods := []string{}
+	for _, o := range ops {
+		methods = append(methods, o.method)
+	}
+	testutil.AssertEqual(t, methods, []string{http.MethodDelete, http.MethodGet, http.MethodPut})
+
+	spec.Paths["/a"]["parameters"] = json.RawMessage(`{"bad": true}`)
+	_, err = spec.operations("/a")
+	testutil.AssertError(t, err)
+}
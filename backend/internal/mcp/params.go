@@ -0,0 +1,21 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BindParams 将工具参数绑定到结构体（按 json tag），类型不匹配时返回 ErrInvalidParams
+func BindParams(params map[string]interface{}, v interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestBindParams(t *testing.T) {
+	type request struct {
+		Name  string `json:"name"`
+		Limit int    `json:"limit"`
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]interface{}
+		want    request
+		wantErr bool
+	}{
+		{name: "正常绑定", params: map[string]interface{}{"name": "a", "limit": float64(10)}, want: request{Name: "a", Limit: 10}},
+		{name: "空参数", params: nil, want: request{}},
+		{name: "类型不匹配", params: map[string]interface{}{"limit": "ten"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got request
+			err := BindParams(tt.params, &got)
+			if tt.wantErr {
+				testutil.AssertEqual(t, errors.Is(err, ErrInvalidParams), true)
+				return
+			}
+			testutil.AssertNoError(t, err)
+			testutil.AssertEqual(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"log"
+	"path"
+	"time"
+)
+
+// ToolOverride 单个工具的配置覆盖
+type ToolOverride struct {
+	Description string
+	Timeout     time.Duration
+}
+
+// ToolPolicy 工具启用策略：Allow 为空表示全部允许，Deny 优先于 Allow，均支持 path.Match 通配符（如 health_*）
+type ToolPolicy struct {
+	Allow     []string
+	Deny      []string
+	Overrides map[string]ToolOverride
+}
+
+// Allowed 工具是否启用
+func (p ToolPolicy) Allowed(name string) bool {
+	if matchAny(p.Deny, name) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchAny(p.Allow, name)
+}
+
+// Timeouts 汇总配置了超时的工具，供 Timeout 拦截器使用
+func (p ToolPolicy) Timeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for name, o := range p.Overrides {
+		if o.Timeout > 0 {
+			timeouts[name] = o.Timeout
+		}
+	}
+	return timeouts
+}
+
+// apply 应用描述覆盖
+func (p ToolPolicy) apply(schema ToolSchema) ToolSchema {
+	if o, ok := p.Overrides[schema.Name]; ok && o.Description != "" {
+		schema.Description = o.Description
+	}
+	return schema
+}
+
+// matchAny 名称是否匹配任一模式
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// policyAdapter 按策略过滤工具注册的适配器装饰器
+type policyAdapter struct {
+	MCPAdapter
+	policy ToolPolicy
+}
+
+// WithToolPolicy 包装适配器：被禁用的工具注册时直接跳过，启用的工具应用描述覆盖。
+// 运行时动态注册的工具同样受策略约束。
+func WithToolPolicy(adapter MCPAdapter, policy ToolPolicy) MCPAdapter {
+	return &policyAdapter{MCPAdapter: adapter, policy: policy}
+}
+
+// RegisterTool 注册工具（被禁用时跳过）
+func (a *policyAdapter) RegisterTool(name string, schema ToolSchema, handler ToolHandler) error {
+	if !a.policy.Allowed(name) {
+		log.Printf("MCP tool %s is disabled by config, skipped", name)
+		return nil
+	}
+	if schema.Name == "" {
+		schema.Name = name
+	}
+	return a.MCPAdapter.RegisterTool(name, a.policy.apply(schema), handler)
+}
+
+// ReplaceTool 替换工具（被禁用时跳过）
+func (a *policyAdapter) ReplaceTool(name string, schema ToolSchema, handler ToolHandler) error {
+	if !a.policy.Allowed(name) {
+		log.Printf("MCP tool %s is disabled by config, skipped", name)
+		return nil
+	}
+	if schema.Name == "" {
+		schema.Name = name
+	}
+	return a.MCPAdapter.ReplaceTool(name, a.policy.apply(schema), handler)
+}
@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestToolPolicy_Allowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy ToolPolicy
+		tool   string
+		want   bool
+	}{
+		{name: "空策略全部启用", tool: "health_check", want: true},
+		{name: "命中 allow", policy: ToolPolicy{Allow: []string{"health_*"}}, tool: "health_check", want: true},
+		{name: "未命中 allow", policy: ToolPolicy{Allow: []string{"health_*"}}, tool: "echo", want: false},
+		{name: "deny 优先于 allow", policy: ToolPolicy{Allow: []string{"*"}, Deny: []string{"echo"}}, tool: "echo", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertEqual(t, tt.policy.Allowed(tt.tool), tt.want)
+		})
+	}
+}
+
+func TestWithToolPolicy(t *testing.T) {
+	policy := ToolPolicy{
+		Deny: []string{"secret"},
+		Overrides: map[string]ToolOverride{
+			"echo": {Description: "overridden", Timeout: 5 * time.Second},
+		},
+	}
+	a := WithToolPolicy(NewMCPAdapter(), policy)
+
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{Description: "echo"}, echoHandler))
+	testutil.AssertNoError(t, a.RegisterTool("secret", ToolSchema{}, echoHandler))
+
+	schema, ok := a.GetTool("echo")
+	testutil.AssertEqual(t, ok, true)
+	testutil.AssertEqual(t, schema.Description, "overridden")
+
+	_, ok = a.GetTool("secret")
+	testutil.AssertEqual(t, ok, false)
+
+	testutil.AssertEqual(t, policy.Timeouts()["echo"], 5*time.Second)
+}
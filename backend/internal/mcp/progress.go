@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// 进度与日志通知方法
+const (
+	MethodProgress   = "notifications/progress"
+	MethodCancelled  = "notifications/cancelled"
+	MethodLogMessage = "notifications/message"
+)
+
+// LogLevel MCP 日志级别（RFC 5424 syslog 级别）
+type LogLevel string
+
+// MCP 日志级别
+const (
+	LogDebug     LogLevel = "debug"
+	LogInfo      LogLevel = "info"
+	LogNotice    LogLevel = "notice"
+	LogWarning   LogLevel = "warning"
+	LogError     LogLevel = "error"
+	LogCritical  LogLevel = "critical"
+	LogAlert     LogLevel = "alert"
+	LogEmergency LogLevel = "emergency"
+)
+
+// logLevelSeverity 日志级别从低到高的排序
+var logLevelSeverity = map[LogLevel]int{
+	LogDebug:     0,
+	LogInfo:      1,
+	LogNotice:    2,
+	LogWarning:   3,
+	LogError:     4,
+	LogCritical:  5,
+	LogAlert:     6,
+	LogEmergency: 7,
+}
+
+// Valid 是否为合法的日志级别
+func (l LogLevel) Valid() bool {
+	_, ok := logLevelSeverity[l]
+	return ok
+}
+
+// NotifyFunc 发送 JSON-RPC 通知的函数，由传输层提供
+type NotifyFunc func(n JSONRPCNotification)
+
+// requestScope 单次请求范围内的通知上下文
+type requestScope struct {
+	progressToken json.RawMessage
+	notify        NotifyFunc
+	session       *Session
+}
+
+type requestScopeKey struct{}
+
+// withRequestScope 写入请求范围上下文
+func withRequestScope(ctx context.Context, scope *requestScope) context.Context {
+	return context.WithValue(ctx, requestScopeKey{}, scope)
+}
+
+// requestScopeFromContext 读取请求范围上下文
+func requestScopeFromContext(ctx context.Context) *requestScope {
+	scope, _ := ctx.Value(requestScopeKey{}).(*requestScope)
+	return scope
+}
+
+// ReportProgress 向客户端发送 notifications/progress；客户端未提供 progressToken 或传输层不支持流式输出时忽略。
+// total <= 0 表示总量未知。
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	scope := requestScopeFromContext(ctx)
+	if scope == nil || len(scope.progressToken) == 0 || scope.notify == nil {
+		return
+	}
+
+	params := map[string]interface{}{
+		"progressToken": scope.progressToken,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	scope.notify(JSONRPCNotification{JSONRPC: JSONRPCVersion, Method: MethodProgress, Params: params})
+}
+
+// Log 向客户端发送 notifications/message 日志，低于客户端通过 logging/setLevel 选择的级别时忽略
+func Log(ctx context.Context, level LogLevel, logger string, data interface{}) {
+	scope := requestScopeFromContext(ctx)
+	if scope == nil || scope.session == nil || !scope.session.shouldLog(level) {
+		return
+	}
+
+	params := map[string]interface{}{
+		"level": level,
+		"data":  data,
+	}
+	if logger != "" {
+		params["logger"] = logger
+	}
+	n := JSONRPCNotification{JSONRPC: JSONRPCVersion, Method: MethodLogMessage, Params: params}
+
+	// 优先随当前请求的流输出，否则走会话级通知通道
+	if scope.notify != nil {
+		scope.notify(n)
+		return
+	}
+	scope.session.notify(n)
+}
@@ -0,0 +1,311 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// 提示词相关通知方法
+const (
+	MethodPromptsListChanged = "notifications/prompts/list_changed"
+)
+
+// 提示词错误定义
+var (
+	ErrPromptNotFound    = errors.New("prompt not found")
+	ErrPromptExists      = errors.New("prompt already registered")
+	ErrInvalidPromptArgs = errors.New("invalid prompt arguments")
+	ErrInvalidPromptFile = errors.New("invalid prompt file")
+)
+
+// 提示词参数类型
+const (
+	PromptArgString  = "string"
+	PromptArgNumber  = "number"
+	PromptArgInteger = "integer"
+	PromptArgBoolean = "boolean"
+)
+
+// 消息角色
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// PromptArgument 提示词参数，Type 为空时按 string 处理
+type PromptArgument struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description"`
+	Required    bool   `json:"required,omitempty" yaml:"required"`
+	Type        string `json:"type,omitempty" yaml:"type"`
+}
+
+// Prompt MCP 提示词模板描述
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+	Scopes      []string         `json:"scopes,omitempty"` // 获取所需权限，启用认证时校验
+}
+
+// PromptMessage 提示词消息
+type PromptMessage struct {
+	Role    string  `json:"role"`
+	Content Content `json:"content"`
+}
+
+// PromptResult 提示词渲染结果
+type PromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptRequest 提示词渲染请求
+type PromptRequest struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+// PromptHandler 提示词渲染函数，args 已按 Prompt.Arguments 校验
+type PromptHandler func(ctx context.Context, args map[string]string) (*PromptResult, error)
+
+// promptEntry 提示词注册项
+type promptEntry struct {
+	prompt  Prompt
+	handler PromptHandler
+}
+
+// promptRegistry 提示词注册表（并发安全）
+type promptRegistry struct {
+	mu      sync.RWMutex
+	prompts map[string]promptEntry
+}
+
+func newPromptRegistry() *promptRegistry {
+	return &promptRegistry{prompts: make(map[string]promptEntry)}
+}
+
+// register 注册提示词
+func (r *promptRegistry) register(prompt Prompt, handler PromptHandler) error {
+	if !toolNamePattern.MatchString(prompt.Name) {
+		return fmt.Errorf("%w: %q", ErrInvalidToolName, prompt.Name)
+	}
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrNilHandler, prompt.Name)
+	}
+	for _, arg := range prompt.Arguments {
+		switch arg.Type {
+		case "", PromptArgString, PromptArgNumber, PromptArgInteger, PromptArgBoolean:
+		default:
+			return fmt.Errorf("%w: argument %s has unsupported type %q", ErrInvalidPromptArgs, arg.Name, arg.Type)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.prompts[prompt.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrPromptExists, prompt.Name)
+	}
+	r.prompts[prompt.Name] = promptEntry{prompt: prompt, handler: handler}
+	return nil
+}
+
+// unregister 注销提示词
+func (r *promptRegistry) unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.prompts[name]; !exists {
+		return fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	delete(r.prompts, name)
+	return nil
+}
+
+// list 列出提示词（按名称排序）
+func (r *promptRegistry) list() []Prompt {
+	r.mu.RLock()
+	prompts := make([]Prompt, 0, len(r.prompts))
+	for _, entry := range r.prompts {
+		prompts = append(prompts, entry.prompt)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(prompts, func(i, j int) bool {
+		return prompts[i].Name < prompts[j].Name
+	})
+	return prompts
+}
+
+// get 校验参数并渲染提示词
+func (r *promptRegistry) get(ctx context.Context, name string, args map[string]string) (*PromptResult, error) {
+	r.mu.RLock()
+	entry, exists := r.prompts[name]
+	r.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+
+	if _, err := convertPromptArgs(entry.prompt.Arguments, args); err != nil {
+		return nil, err
+	}
+	if args == nil {
+		args = map[string]string{}
+	}
+	return entry.handler(ctx, args)
+}
+
+// convertPromptArgs 校验必填、未知参数与类型，返回按类型转换后的参数
+func convertPromptArgs(defs []PromptArgument, args map[string]string) (map[string]interface{}, error) {
+	known := make(map[string]PromptArgument, len(defs))
+	for _, def := range defs {
+		known[def.Name] = def
+	}
+	for name := range args {
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown argument %q", ErrInvalidPromptArgs, name)
+		}
+	}
+
+	values := make(map[string]interface{}, len(defs))
+	for _, def := range defs {
+		raw, ok := args[def.Name]
+		if !ok || raw == "" {
+			if def.Required {
+				return nil, fmt.Errorf("%w: missing required argument %q", ErrInvalidPromptArgs, def.Name)
+			}
+			values[def.Name] = ""
+			continue
+		}
+
+		var (
+			value interface{} = raw
+			err   error
+		)
+		switch def.Type {
+		case PromptArgNumber:
+			value, err = strconv.ParseFloat(raw, 64)
+		case PromptArgInteger:
+			value, err = strconv.ParseInt(raw, 10, 64)
+		case PromptArgBoolean:
+			value, err = strconv.ParseBool(raw)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: argument %q must be %s", ErrInvalidPromptArgs, def.Name, def.Type)
+		}
+		values[def.Name] = value
+	}
+	return values, nil
+}
+
+// NewTemplatePrompt 基于 text/template 创建提示词处理函数，模板中以 {{.参数名}} 引用已按类型转换的参数
+func NewTemplatePrompt(prompt Prompt, role, body string) (PromptHandler, error) {
+	if role == "" {
+		role = RoleUser
+	}
+	if role != RoleUser && role != RoleAssistant {
+		return nil, fmt.Errorf("%w: unsupported role %q", ErrInvalidPromptFile, role)
+	}
+
+	tmpl, err := template.New(prompt.Name).Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse prompt %s: %w", prompt.Name, err)
+	}
+
+	return func(ctx context.Context, args map[string]string) (*PromptResult, error) {
+		values, err := convertPromptArgs(prompt.Arguments, args)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, values); err != nil {
+			return nil, fmt.Errorf("render prompt %s: %w", prompt.Name, err)
+		}
+
+		return &PromptResult{
+			Description: prompt.Description,
+			Messages: []PromptMessage{{
+				Role:    role,
+				Content: TextContent(strings.TrimSpace(buf.String())),
+			}},
+		}, nil
+	}, nil
+}
+
+// promptFrontMatter 提示词文件的 front-matter
+type promptFrontMatter struct {
+	Name        string           `yaml:"name"`
+	Description string           `yaml:"description"`
+	Role        string           `yaml:"role"`
+	Arguments   []PromptArgument `yaml:"arguments"`
+	Scopes      []string         `yaml:"scopes"`
+}
+
+// LoadPromptsDir 从目录加载 markdown 提示词模板（YAML front-matter + text/template 正文），返回加载数量
+func LoadPromptsDir(adapter MCPAdapter, dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		prompt, handler, err := parsePromptFile(file)
+		if err != nil {
+			return 0, err
+		}
+		if err := adapter.RegisterPrompt(prompt, handler); err != nil {
+			return 0, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return len(files), nil
+}
+
+// parsePromptFile 解析单个提示词文件，未声明 name 时使用文件名
+func parsePromptFile(path string) (Prompt, PromptHandler, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Prompt{}, nil, err
+	}
+
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var meta promptFrontMatter
+	body := content
+	if strings.HasPrefix(content, "---\n") {
+		end := strings.Index(content[4:], "\n---")
+		if end < 0 {
+			return Prompt{}, nil, fmt.Errorf("%w: %s: unterminated front-matter", ErrInvalidPromptFile, path)
+		}
+		if err := yaml.Unmarshal([]byte(content[4:4+end]), &meta); err != nil {
+			return Prompt{}, nil, fmt.Errorf("%w: %s: %v", ErrInvalidPromptFile, path, err)
+		}
+		body = strings.TrimPrefix(content[4+end+len("\n---"):], "\n")
+	}
+
+	if meta.Name == "" {
+		meta.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	prompt := Prompt{
+		Name:        meta.Name,
+		Description: meta.Description,
+		Arguments:   meta.Arguments,
+		Scopes:      meta.Scopes,
+	}
+	handler, err := NewTemplatePrompt(prompt, meta.Role, body)
+	if err != nil {
+		return Prompt{}, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return prompt, handler, nil
+}
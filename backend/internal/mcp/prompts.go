@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/spf13/viper"
+)
+
+// RegisterAllPrompts 统一注册所有 MCP 提示词：先注册代码中定义的提示词，再加载 mcp.prompts_dir 目录
+func RegisterAllPrompts(adapter MCPAdapter) error {
+	// 注册健康报告提示词
+	if err := registerHealthReportPrompt(adapter); err != nil {
+		log.Printf("Failed to register health report prompt: %v", err)
+		return err
+	}
+
+	// 加载提示词目录（目录不存在时跳过）
+	if dir := viper.GetString("mcp.prompts_dir"); dir != "" {
+		if _, err := os.Stat(dir); err == nil {
+			if _, err := LoadPromptsDir(adapter, dir); err != nil {
+				log.Printf("Failed to load prompts from %s: %v", dir, err)
+				return err
+			}
+		}
+	}
+
+	log.Printf("Successfully registered %d MCP prompts", len(adapter.ListPrompts()))
+	return nil
+}
+
+// registerHealthReportPrompt 注册健康报告提示词
+func registerHealthReportPrompt(adapter MCPAdapter) error {
+	prompt := Prompt{
+		Name:        "health_report",
+		Description: "生成系统健康巡检报告",
+		Arguments: []PromptArgument{
+			{Name: "audience", Description: "报告读者，如 运维、研发", Required: false},
+		},
+	}
+
+	handler := func(ctx context.Context, args map[string]string) (*PromptResult, error) {
+		audience := args["audience"]
+		if audience == "" {
+			audience = "研发"
+		}
+
+		text := fmt.Sprintf("请调用 health_check 工具并读取 log://app 资源，面向%s团队输出一份简洁的系统健康巡检报告，包含当前状态、版本和近期异常。", audience)
+		return &PromptResult{
+			Description: prompt.Description,
+			Messages:    []PromptMessage{{Role: RoleUser, Content: TextContent(text)}},
+		}, nil
+	}
+
+	return adapter.RegisterPrompt(prompt, handler)
+}
@@ -0,0 +1,384 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CodeRateLimited 工具调用被限流时的 JSON-RPC 错误码（实现自定义错误码区间）
+const CodeRateLimited = -32029
+
+// 限流维度
+const (
+	RateLimitScopeGlobal = "global"
+	RateLimitScopeTool   = "tool"
+	RateLimitScopeClient = "client"
+)
+
+// ErrRateLimited 工具调用被限流
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Limit 令牌桶配置：每秒补充 Rate 个令牌，桶容量为 Burst；Rate <= 0 表示不限制
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute 每分钟 n 次、突发 burst 次的限制，burst <= 0 时取 n
+func PerMinute(n, burst int) Limit {
+	if burst <= 0 {
+		burst = n
+	}
+	return Limit{Rate: float64(n) / 60, Burst: burst}
+}
+
+// Unlimited 是否不限制
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// burst 桶容量，至少为 1
+func (l Limit) burst() float64 {
+	if l.Burst < 1 {
+		return 1
+	}
+	return float64(l.Burst)
+}
+
+// RateLimitError 限流错误，携带建议的重试等待时间
+type RateLimitError struct {
+	Scope      string
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s for %s %s: retry after %s", ErrRateLimited, e.Scope, e.Key, e.RetryAfter)
+}
+
+// Is 支持 errors.Is(err, ErrRateLimited)
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RetryAfterSeconds 向上取整的重试等待秒数，用于 Retry-After 头与 JSON-RPC 错误数据
+func (e *RateLimitError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// RateLimitStore 令牌桶存储
+type RateLimitStore interface {
+	// Take 从 key 对应的令牌桶取一个令牌，令牌不足时返回 false 及需要等待的时间
+	Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+	// Refund 归还一个已取出的令牌（其他维度超限、调用未执行时）
+	Refund(ctx context.Context, key string, limit Limit) error
+}
+
+// RateLimitOptions 限流配置
+type RateLimitOptions struct {
+	Store     RateLimitStore   // 为空时使用内存存储
+	Global    Limit            // 所有调用共享
+	PerTool   Limit            // 每个工具的默认限制
+	PerClient Limit            // 每个调用方（API Key / Token subject）的默认限制
+	Tools     map[string]Limit // 单个工具的限制，覆盖 PerTool
+	Clients   map[string]Limit // 单个调用方的限制，覆盖 PerClient
+}
+
+// RateLimiter 按全局、工具、调用方三个维度限流，并统计被限流的调用
+type RateLimiter struct {
+	opts RateLimitOptions
+
+	mu        sync.Mutex
+	allowed   uint64
+	throttled map[string]uint64 // 维度 -> 次数
+	byTool    map[string]uint64 // 工具 -> 次数
+	storeErrs uint64
+}
+
+// NewRateLimiter 创建限流器
+func NewRateLimiter(opts RateLimitOptions) *RateLimiter {
+	if opts.Store == nil {
+		opts.Store = NewMemoryRateLimitStore()
+	}
+	return &RateLimiter{
+		opts:      opts,
+		throttled: make(map[string]uint64),
+		byTool:    make(map[string]uint64),
+	}
+}
+
+// Allow 检查本次调用是否允许，超限时返回 *RateLimitError；存储不可用时放行并记录日志
+func (l *RateLimiter) Allow(ctx context.Context, tool string) error {
+	client := ""
+	if p, ok := PrincipalFromContext(ctx); ok {
+		client = p.Subject
+	}
+
+	var taken []rateLimitCheck
+	for _, c := range l.checks(tool, client) {
+		ok, wait, err := l.opts.Store.Take(ctx, c.key(), c.limit)
+		if err != nil {
+			slog.WarnContext(ctx, "mcp rate limit store unavailable, allowing call", "tool", tool, "error", err)
+			l.mu.Lock()
+			l.storeErrs++
+			l.mu.Unlock()
+			continue
+		}
+		if !ok {
+			// 被拒绝的调用不消耗其他维度的配额
+			l.refund(ctx, taken)
+			l.mu.Lock()
+			l.throttled[c.scope]++
+			l.byTool[tool]++
+			l.mu.Unlock()
+			return &RateLimitError{Scope: c.scope, Key: c.name, RetryAfter: wait}
+		}
+		taken = append(taken, c)
+	}
+
+	l.mu.Lock()
+	l.allowed++
+	l.mu.Unlock()
+	return nil
+}
+
+// refund 归还已取出的令牌，失败时只记录日志
+func (l *RateLimiter) refund(ctx context.Context, taken []rateLimitCheck) {
+	for _, c := range taken {
+		if err := l.opts.Store.Refund(ctx, c.key(), c.limit); err != nil {
+			slog.WarnContext(ctx, "mcp rate limit refund failed", "scope", c.scope, "key", c.name, "error", err)
+			l.mu.Lock()
+			l.storeErrs++
+			l.mu.Unlock()
+		}
+	}
+}
+
+// rateLimitCheck 单个维度的检查项
+type rateLimitCheck struct {
+	scope string
+	name  string
+	limit Limit
+}
+
+// key 令牌桶在存储中的 key
+func (c rateLimitCheck) key() string {
+	return c.scope + ":" + c.name
+}
+
+// checks 由细到粗列出需要检查的令牌桶，调用方最先检查，避免单个调用方耗尽全局配额
+func (l *RateLimiter) checks(tool, client string) []rateLimitCheck {
+	var checks []rateLimitCheck
+	if client != "" {
+		limit, ok := l.opts.Clients[client]
+		if !ok {
+			limit = l.opts.PerClient
+		}
+		if !limit.Unlimited() {
+			checks = append(checks, rateLimitCheck{RateLimitScopeClient, client, limit})
+		}
+	}
+
+	limit, ok := l.opts.Tools[tool]
+	if !ok {
+		limit = l.opts.PerTool
+	}
+	if !limit.Unlimited() {
+		checks = append(checks, rateLimitCheck{RateLimitScopeTool, tool, limit})
+	}
+
+	if !l.opts.Global.Unlimited() {
+		checks = append(checks, rateLimitCheck{RateLimitScopeGlobal, "*", l.opts.Global})
+	}
+	return checks
+}
+
+// Interceptor 限流拦截器，应放在鉴权之后，避免未授权调用消耗配额
+func (l *RateLimiter) Interceptor() Interceptor {
+	return func(next ToolHandler) ToolHandler {
+		return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			info, _ := CallInfoFromContext(ctx)
+			if err := l.Allow(ctx, info.Tool); err != nil {
+				slog.WarnContext(ctx, "mcp tool call throttled", "tool", info.Tool, "error", err)
+				return nil, err
+			}
+			return next(ctx, params)
+		}
+	}
+}
+
+// RateLimitStats 限流统计
+type RateLimitStats struct {
+	Allowed     uint64            `json:"allowed"`
+	Throttled   uint64            `json:"throttled"`
+	ByScope     map[string]uint64 `json:"by_scope"`
+	ByTool      map[string]uint64 `json:"by_tool"`
+	StoreErrors uint64            `json:"store_errors"`
+}
+
+// Stats 返回限流统计快照
+func (l *RateLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := RateLimitStats{
+		Allowed:     l.allowed,
+		ByScope:     make(map[string]uint64, len(l.throttled)),
+		ByTool:      make(map[string]uint64, len(l.byTool)),
+		StoreErrors: l.storeErrs,
+	}
+	for scope, n := range l.throttled {
+		stats.ByScope[scope] = n
+		stats.Throttled += n
+	}
+	for tool, n := range l.byTool {
+		stats.ByTool[tool] = n
+	}
+	return stats
+}
+
+// bucketSweepInterval 内存存储清理已补满的令牌桶的间隔
+const bucketSweepInterval = time.Minute
+
+// MemoryRateLimitStore 进程内令牌桶存储，适用于单实例部署
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+// tokenBucket 令牌桶状态
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // 补满的时间，此后与新建的桶等价，可以清理
+}
+
+// NewMemoryRateLimitStore 创建内存存储
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// sweep 按间隔清理已补满的令牌桶，避免调用方或工具较多时桶数量无限增长
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < bucketSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// Take 取一个令牌
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: limit.burst(), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(limit.burst(), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	b.full = now.Add(time.Duration((limit.burst() - b.tokens) / limit.Rate * float64(time.Second)))
+	if allowed {
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// Refund 归还一个令牌，不超过突发容量
+func (s *MemoryRateLimitStore) Refund(ctx context.Context, key string, limit Limit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b, ok := s.buckets[key]; ok {
+		b.tokens = math.Min(limit.burst(), b.tokens+1)
+		b.full = b.last.Add(time.Duration((limit.burst() - b.tokens) / limit.Rate * float64(time.Second)))
+	}
+	return nil
+}
+
+// tokenBucketScript 原子地补充并扣减令牌，返回需要等待的毫秒数（0 表示成功）；以 EVALSHA 执行，脚本未缓存时回退为 EVAL
+var tokenBucketScript = redis.NewScript(`
+redis.replicate_commands()
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) / 1000 * rate)
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+else
+  wait = math.ceil((1 - tokens) / rate * 1000)
+end
+-- 以定点格式保存，避免 5e-05 这类科学计数法在部分 Lua 实现中无法解析
+redis.call('HSET', KEYS[1], 'tokens', string.format('%.10f', tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return wait
+`)
+
+// refundScript 归还一个令牌，不超过突发容量；桶已过期时无需归还
+var refundScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local tokens = tonumber(redis.call('HGET', KEYS[1], 'tokens'))
+if tokens then
+  redis.call('HSET', KEYS[1], 'tokens', string.format('%.10f', math.min(burst, tokens + 1)))
+end
+return 0
+`)
+
+// RedisRateLimitStore 基于 Redis 的令牌桶存储，多实例部署时共享配额
+type RedisRateLimitStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+// NewRedisRateLimitStore 创建 Redis 存储，prefix 为空时使用 "mcp:ratelimit:"
+func NewRedisRateLimitStore(client redis.Scripter, prefix string) *RedisRateLimitStore {
+	if prefix == "" {
+		prefix = "mcp:ratelimit:"
+	}
+	return &RedisRateLimitStore{client: client, prefix: prefix}
+}
+
+// Take 取一个令牌
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	wait, err := tokenBucketScript.Run(ctx, s.client, []string{s.prefix + key}, limit.Rate, limit.burst()).Int64()
+	if err != nil {
+		return false, 0, err
+	}
+	if wait > 0 {
+		return false, time.Duration(wait) * time.Millisecond, nil
+	}
+	return true, 0, nil
+}
+
+// Refund 归还一个令牌
+func (s *RedisRateLimitStore) Refund(ctx context.Context, key string, limit Limit) error {
+	return refundScript.Run(ctx, s.client, []string{s.prefix + key}, limit.burst()).Err()
+}
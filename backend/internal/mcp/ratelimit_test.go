@@ -0,0 +1,269 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := store.Take(ctx, "k", limit)
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, ok, true)
+	}
+
+	ok, wait, _ := store.Take(ctx, "k", limit)
+	testutil.AssertEqual(t, ok, false)
+	testutil.AssertEqual(t, wait, time.Second)
+
+	// 半秒后仍不足一个令牌
+	now = now.Add(500 * time.Millisecond)
+	ok, wait, _ = store.Take(ctx, "k", limit)
+	testutil.AssertEqual(t, ok, false)
+	testutil.AssertEqual(t, wait, 500*time.Millisecond)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _, _ = store.Take(ctx, "k", limit)
+	testutil.AssertEqual(t, ok, true)
+
+	// 不同 key 互不影响
+	ok, _, _ = store.Take(ctx, "other", limit)
+	testutil.AssertEqual(t, ok, true)
+}
+
+func TestRateLimiter_Allow(t *testing.T) {
+	client := func(name string) context.Context {
+		return WithPrincipal(context.Background(), &Principal{Subject: name})
+	}
+
+	tests := []struct {
+		name      string
+		opts      RateLimitOptions
+		calls     []context.Context
+		tool      string
+		wantScope string // 最后一次调用被限流的维度，为空表示放行
+	}{
+		{
+			name:  "不限制",
+			calls: []context.Context{client("a"), client("a"), client("a")},
+			tool:  "echo",
+		},
+		{
+			name:      "全局限制",
+			opts:      RateLimitOptions{Global: PerMinute(2, 0)},
+			calls:     []context.Context{client("a"), client("b"), context.Background()},
+			tool:      "echo",
+			wantScope: RateLimitScopeGlobal,
+		},
+		{
+			name:      "单个工具限制",
+			opts:      RateLimitOptions{Tools: map[string]Limit{"echo": PerMinute(1, 0)}},
+			calls:     []context.Context{client("a"), client("b")},
+			tool:      "echo",
+			wantScope: RateLimitScopeTool,
+		},
+		{
+			name:  "单个工具限制不影响其他工具",
+			opts:  RateLimitOptions{Tools: map[string]Limit{"other": PerMinute(1, 0)}},
+			calls: []context.Context{client("a"), client("b")},
+			tool:  "echo",
+		},
+		{
+			name:      "调用方默认限制",
+			opts:      RateLimitOptions{PerClient: PerMinute(1, 0)},
+			calls:     []context.Context{client("a"), client("a")},
+			tool:      "echo",
+			wantScope: RateLimitScopeClient,
+		},
+		{
+			name:  "调用方之间独立",
+			opts:  RateLimitOptions{PerClient: PerMinute(1, 0)},
+			calls: []context.Context{client("a"), client("b")},
+			tool:  "echo",
+		},
+		{
+			name: "单个调用方覆盖默认限制",
+			opts: RateLimitOptions{
+				PerClient: PerMinute(1, 0),
+				Clients:   map[string]Limit{"ci": PerMinute(3, 0)},
+			},
+			calls: []context.Context{client("ci"), client("ci"), client("ci")},
+			tool:  "echo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewRateLimiter(tt.opts)
+			var err error
+			for _, ctx := range tt.calls {
+				err = l.Allow(ctx, tt.tool)
+			}
+
+			if tt.wantScope == "" {
+				testutil.AssertNoError(t, err)
+				return
+			}
+			var limitErr *RateLimitError
+			testutil.AssertEqual(t, errors.As(err, &limitErr), true)
+			testutil.AssertEqual(t, errors.Is(err, ErrRateLimited), true)
+			testutil.AssertEqual(t, limitErr.Scope, tt.wantScope)
+			testutil.AssertEqual(t, limitErr.RetryAfter > 0, true)
+			testutil.AssertEqual(t, l.Stats().ByScope[tt.wantScope], uint64(1))
+		})
+	}
+}
+
+func TestRateLimiter_Interceptor(t *testing.T) {
+	a := NewMCPAdapter()
+	l := NewRateLimiter(RateLimitOptions{Tools: map[string]Limit{"echo": PerMinute(1, 0)}})
+	a.Use(l.Interceptor())
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{}, echoHandler))
+	ctx := context.Background()
+
+	t.Run("REST 接口返回限流错误", func(t *testing.T) {
+		resp, err := a.HandleRequest(ctx, &MCPRequest{Tool: "echo"})
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, resp.Success, true)
+
+		_, err = a.HandleRequest(ctx, &MCPRequest{Tool: "echo"})
+		testutil.AssertEqual(t, errors.Is(err, ErrRateLimited), true)
+	})
+
+	t.Run("JSON-RPC 返回带重试时间的错误", func(t *testing.T) {
+		sess := newSession(t, a)
+		resp := sess.Handle(ctx, newRequest(1, "tools/call", map[string]interface{}{"name": "echo"}), nil)
+
+		testutil.AssertNotNil(t, resp.Error)
+		testutil.AssertEqual(t, resp.Error.Code, CodeRateLimited)
+		data := resp.Error.Data.(map[string]interface{})
+		testutil.AssertEqual(t, data["scope"], RateLimitScopeTool)
+		testutil.AssertEqual(t, data["retryAfter"], 60)
+	})
+
+	stats := l.Stats()
+	testutil.AssertEqual(t, stats.Allowed, uint64(1))
+	testutil.AssertEqual(t, stats.Throttled, uint64(2))
+	testutil.AssertEqual(t, stats.ByTool["echo"], uint64(2))
+}
+
+func TestRedisRateLimitStore_Take(t *testing.T) {
+	ctx := context.Background()
+	srv := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+	defer client.Close()
+
+	t.Run("放行", func(t *testing.T) {
+		ok, _, err := NewRedisRateLimitStore(client, "").Take(ctx, "tool:echo", PerMinute(60, 0))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, ok, true)
+		testutil.AssertEqual(t, srv.Exists("mcp:ratelimit:tool:echo"), true)
+	})
+
+	t.Run("限流", func(t *testing.T) {
+		store := NewRedisRateLimitStore(client, "p:")
+		ok, _, err := store.Take(ctx, "global:*", PerMinute(60, 1))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, ok, true)
+
+		ok, wait, err := store.Take(ctx, "global:*", PerMinute(60, 1))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, ok, false)
+		testutil.AssertEqual(t, wait > 0 && wait <= time.Second, true)
+	})
+
+	t.Run("脚本缓存被清空后重新加载", func(t *testing.T) {
+		testutil.AssertNoError(t, client.ScriptFlush(ctx).Err())
+		ok, _, err := NewRedisRateLimitStore(client, "").Take(ctx, "tool:reload", PerMinute(60, 0))
+		testutil.AssertNoError(t, err)
+		testutil.AssertEqual(t, ok, true)
+	})
+
+	t.Run("存储不可用时放行", func(t *testing.T) {
+		down := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		defer down.Close()
+		l := NewRateLimiter(RateLimitOptions{Store: NewRedisRateLimitStore(down, ""), Global: PerMinute(1, 0)})
+		testutil.AssertNoError(t, l.Allow(ctx, "echo"))
+		testutil.AssertEqual(t, l.Stats().StoreErrors, uint64(1))
+	})
+}
+
+func TestRateLimiter_RefundOnThrottle(t *testing.T) {
+	ctx := WithPrincipal(context.Background(), &Principal{Subject: "alice"})
+	opts := RateLimitOptions{
+		PerClient: PerMinute(2, 0),
+		Tools:     map[string]Limit{"slow": PerMinute(1, 0)},
+	}
+
+	run := func(t *testing.T, store RateLimitStore) {
+		opts.Store = store
+		l := NewRateLimiter(opts)
+		testutil.AssertNoError(t, l.Allow(ctx, "slow"))
+
+		// 被工具限制拒绝的调用不消耗调用方配额
+		for i := 0; i < 3; i++ {
+			var rlErr *RateLimitError
+			testutil.AssertEqual(t, errors.As(l.Allow(ctx, "slow"), &rlErr), true)
+			testutil.AssertEqual(t, rlErr.Scope, RateLimitScopeTool)
+		}
+		testutil.AssertNoError(t, l.Allow(ctx, "echo"))
+		testutil.AssertEqual(t, l.Stats().StoreErrors, uint64(0))
+	}
+
+	t.Run("内存存储", func(t *testing.T) {
+		store := NewMemoryRateLimitStore()
+		now := time.Unix(0, 0)
+		store.now = func() time.Time { return now }
+		run(t, store)
+	})
+
+	t.Run("Redis 存储", func(t *testing.T) {
+		srv := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: srv.Addr()})
+		defer client.Close()
+		run(t, NewRedisRateLimitStore(client, ""))
+	})
+}
+
+func TestMemoryRateLimitStore_Evict(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryRateLimitStore()
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// 每秒补充 1 个令牌，突发 2：a 取 1 个后 1 秒补满，b 取 2 个后 2 秒补满
+	limit := Limit{Rate: 1, Burst: 2}
+	s.Take(ctx, "a", limit)
+	s.Take(ctx, "b", limit)
+	s.Take(ctx, "b", limit)
+	testutil.AssertEqual(t, len(s.buckets), 2)
+
+	now = now.Add(bucketSweepInterval)
+	s.Take(ctx, "c", limit)
+	testutil.AssertEqual(t, len(s.buckets), 1)
+
+	// c 取完 2 个令牌，2 秒后才补满，期间不清理
+	s.Take(ctx, "c", limit)
+	now = now.Add(1500 * time.Millisecond)
+	s.lastSweep = time.Time{}
+	s.sweep(now)
+	_, kept := s.buckets["c"]
+	testutil.AssertEqual(t, kept, true)
+
+	now = now.Add(time.Second)
+	s.lastSweep = time.Time{}
+	s.sweep(now)
+	testutil.AssertEqual(t, len(s.buckets), 0)
+}
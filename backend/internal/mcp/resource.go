@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 资源相关通知方法
+const (
+	MethodResourcesListChanged = "notifications/resources/list_changed"
+	MethodResourcesUpdated     = "notifications/resources/updated"
+)
+
+// 资源错误定义
+var (
+	ErrResourceNotFound   = errors.New("resource not found")
+	ErrResourceExists     = errors.New("resource already registered")
+	ErrInvalidResourceURI = errors.New("invalid resource uri")
+)
+
+// Resource MCP 资源描述
+type Resource struct {
+	URI         string   `json:"uri"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	MimeType    string   `json:"mimeType,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"` // 读取与订阅所需权限，启用认证时校验
+}
+
+// ResourceTemplate MCP 资源模板，URITemplate 使用 RFC 6570 的简单变量形式，如 db://users/{id}
+type ResourceTemplate struct {
+	URITemplate string   `json:"uriTemplate"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	MimeType    string   `json:"mimeType,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"` // 读取与订阅所需权限，启用认证时校验
+}
+
+// ResourceContents 资源内容，文本放在 Text，二进制内容以 base64 放在 Blob
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// ResourceList 资源列表
+type ResourceList struct {
+	Resources         []Resource         `json:"resources"`
+	ResourceTemplates []ResourceTemplate `json:"resourceTemplates"`
+}
+
+// ResourceHandler 资源读取函数，vars 为从 URI 模板中解析出的变量（静态资源为空）
+type ResourceHandler func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error)
+
+// resourceEntry 静态资源注册项
+type resourceEntry struct {
+	resource Resource
+	handler  ResourceHandler
+}
+
+// templateEntry 资源模板注册项
+type templateEntry struct {
+	template ResourceTemplate
+	matcher  *uriTemplate
+	handler  ResourceHandler
+}
+
+// resourceRegistry 资源注册表（并发安全）
+type resourceRegistry struct {
+	mu        sync.RWMutex
+	resources map[string]resourceEntry
+	templates map[string]templateEntry
+}
+
+func newResourceRegistry() *resourceRegistry {
+	return &resourceRegistry{
+		resources: make(map[string]resourceEntry),
+		templates: make(map[string]templateEntry),
+	}
+}
+
+// register 注册静态资源
+func (r *resourceRegistry) register(resource Resource, handler ResourceHandler) error {
+	if _, err := url.Parse(resource.URI); err != nil || !strings.Contains(resource.URI, "://") {
+		return fmt.Errorf("%w: %q", ErrInvalidResourceURI, resource.URI)
+	}
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrNilHandler, resource.URI)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.resources[resource.URI]; exists {
+		return fmt.Errorf("%w: %s", ErrResourceExists, resource.URI)
+	}
+	r.resources[resource.URI] = resourceEntry{resource: resource, handler: handler}
+	return nil
+}
+
+// registerTemplate 注册资源模板
+func (r *resourceRegistry) registerTemplate(tmpl ResourceTemplate, handler ResourceHandler) error {
+	matcher, err := parseURITemplate(tmpl.URITemplate)
+	if err != nil {
+		return err
+	}
+	if handler == nil {
+		return fmt.Errorf("%w: %s", ErrNilHandler, tmpl.URITemplate)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.templates[tmpl.URITemplate]; exists {
+		return fmt.Errorf("%w: %s", ErrResourceExists, tmpl.URITemplate)
+	}
+	r.templates[tmpl.URITemplate] = templateEntry{template: tmpl, matcher: matcher, handler: handler}
+	return nil
+}
+
+// unregister 注销静态资源或资源模板
+func (r *resourceRegistry) unregister(uri string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.resources[uri]; exists {
+		delete(r.resources, uri)
+		return nil
+	}
+	if _, exists := r.templates[uri]; exists {
+		delete(r.templates, uri)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+}
+
+// list 列出静态资源（按 URI 排序）
+func (r *resourceRegistry) list() []Resource {
+	r.mu.RLock()
+	resources := make([]Resource, 0, len(r.resources))
+	for _, entry := range r.resources {
+		resources = append(resources, entry.resource)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].URI < resources[j].URI
+	})
+	return resources
+}
+
+// listTemplates 列出资源模板（按模板排序）
+func (r *resourceRegistry) listTemplates() []ResourceTemplate {
+	r.mu.RLock()
+	templates := make([]ResourceTemplate, 0, len(r.templates))
+	for _, entry := range r.templates {
+		templates = append(templates, entry.template)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].URITemplate < templates[j].URITemplate
+	})
+	return templates
+}
+
+// read 读取资源，静态资源优先于模板匹配
+func (r *resourceRegistry) read(ctx context.Context, uri string) ([]ResourceContents, error) {
+	r.mu.RLock()
+	entry, exists := r.resources[uri]
+	var (
+		handler ResourceHandler
+		vars    map[string]string
+	)
+	if exists {
+		handler = entry.handler
+	} else {
+		// 按模板字符串排序匹配，保证结果确定
+		keys := make([]string, 0, len(r.templates))
+		for k := range r.templates {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if v, ok := r.templates[k].matcher.match(uri); ok {
+				handler, vars = r.templates[k].handler, v
+				break
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	if handler == nil {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+	return handler(ctx, uri, vars)
+}
+
+// uriTemplate 简化的 RFC 6570 模板匹配器，支持 {var}（不跨越 /）与 {+var}（可跨越 /）
+type uriTemplate struct {
+	re   *regexp.Regexp
+	vars []string
+}
+
+var templateVarPattern = regexp.MustCompile(`\{(\+?)([A-Za-z0-9_]+)\}`)
+
+// parseURITemplate 解析 URI 模板
+func parseURITemplate(tmpl string) (*uriTemplate, error) {
+	if !strings.Contains(tmpl, "://") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidResourceURI, tmpl)
+	}
+
+	var (
+		pattern strings.Builder
+		vars    []string
+		last    int
+	)
+	pattern.WriteString("^")
+	for _, m := range templateVarPattern.FindAllStringSubmatchIndex(tmpl, -1) {
+		pattern.WriteString(regexp.QuoteMeta(tmpl[last:m[0]]))
+		if m[3] > m[2] {
+			pattern.WriteString("(.+)")
+		} else {
+			pattern.WriteString("([^/]+)")
+		}
+		vars = append(vars, tmpl[m[4]:m[5]])
+		last = m[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(tmpl[last:]))
+	pattern.WriteString("$")
+
+	if len(vars) == 0 {
+		return nil, fmt.Errorf("%w: template %q has no variables", ErrInvalidResourceURI, tmpl)
+	}
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResourceURI, err)
+	}
+	return &uriTemplate{re: re, vars: vars}, nil
+}
+
+// match 匹配 URI 并返回变量值
+func (t *uriTemplate) match(uri string) (map[string]string, bool) {
+	m := t.re.FindStringSubmatch(uri)
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string, len(t.vars))
+	for i, name := range t.vars {
+		value, err := url.PathUnescape(m[i+1])
+		if err != nil {
+			value = m[i+1]
+		}
+		vars[name] = value
+	}
+	return vars, true
+}
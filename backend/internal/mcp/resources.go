@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/richer/ai_skeleton/internal/service/health"
+	"github.com/spf13/viper"
+)
+
+// defaultLogTailLines 日志资源默认返回的行数
+const defaultLogTailLines = 200
+
+// configResourceSections 允许通过 config:// 读取的配置段；database、mcp 等配置段包含凭据，不对外暴露
+var configResourceSections = map[string]bool{"project": true, "server": true}
+
+// 内置资源读取所需的权限
+const (
+	ConfigReadScope = "config:read"
+	HealthReadScope = "health:read"
+	LogsReadScope   = "logs:read"
+)
+
+// configRedactFields 配置资源额外脱敏的字段（不区分大小写），以 _key 结尾的字段同样脱敏
+var configRedactFields = []string{"key", "dsn", "access_key", "secret_key", "api_secret"}
+
+// RegisterAllResources 统一注册所有 MCP 资源
+func RegisterAllResources(adapter MCPAdapter) error {
+	registers := []func(MCPAdapter) error{
+		registerConfigResources,
+		registerHealthResource,
+		registerLogResources,
+	}
+
+	for _, register := range registers {
+		if err := register(adapter); err != nil {
+			log.Printf("Failed to register MCP resource: %v", err)
+			return err
+		}
+	}
+
+	log.Printf("Successfully registered %d MCP resources and %d resource templates",
+		len(adapter.ListResources()), len(adapter.ListResourceTemplates()))
+	return nil
+}
+
+// registerConfigResources 注册配置资源，敏感字段会被脱敏
+func registerConfigResources(adapter MCPAdapter) error {
+	err := adapter.RegisterResource(Resource{
+		URI:         "config://project",
+		Name:        "project",
+		Description: "项目基础信息（config.yaml 的 project 部分）",
+		MimeType:    "application/json",
+		Scopes:      []string{ConfigReadScope},
+	}, func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		return configSectionContents(uri, "project")
+	})
+	if err != nil {
+		return err
+	}
+
+	return adapter.RegisterResourceTemplate(ResourceTemplate{
+		URITemplate: "config://{section}",
+		Name:        "config_section",
+		Description: "按名称读取 config.yaml 中的配置段（仅支持 project、server），敏感字段已脱敏",
+		MimeType:    "application/json",
+		Scopes:      []string{ConfigReadScope},
+	}, func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		return configSectionContents(uri, vars["section"])
+	})
+}
+
+// registerHealthResource 注册健康状态资源
+func registerHealthResource(adapter MCPAdapter) error {
+	return adapter.RegisterResource(Resource{
+		URI:         "health://status",
+		Name:        "health_status",
+		Description: "系统当前健康状态",
+		MimeType:    "application/json",
+		Scopes:      []string{HealthReadScope},
+	}, func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		svc := health.NewHealthService()
+		result, err := svc.Check(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return jsonContents(uri, result)
+	})
+}
+
+// registerLogResources 注册日志尾部资源
+func registerLogResources(adapter MCPAdapter) error {
+	handler := func(ctx context.Context, uri string, vars map[string]string) ([]ResourceContents, error) {
+		lines := defaultLogTailLines
+		if v, ok := vars["lines"]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid lines: %q", v)
+			}
+			lines = n
+		}
+
+		text, err := tailFile(viper.GetString("logging.file_path"), lines)
+		if err != nil {
+			return nil, err
+		}
+		return []ResourceContents{{URI: uri, MimeType: "text/plain", Text: text}}, nil
+	}
+
+	err := adapter.RegisterResource(Resource{
+		URI:         "log://app",
+		Name:        "app_log",
+		Description: fmt.Sprintf("应用日志最后 %d 行", defaultLogTailLines),
+		MimeType:    "text/plain",
+		Scopes:      []string{LogsReadScope},
+	}, handler)
+	if err != nil {
+		return err
+	}
+
+	return adapter.RegisterResourceTemplate(ResourceTemplate{
+		URITemplate: "log://app/tail/{lines}",
+		Name:        "app_log_tail",
+		Description: "应用日志最后 N 行",
+		MimeType:    "text/plain",
+		Scopes:      []string{LogsReadScope},
+	}, handler)
+}
+
+// configSectionContents 读取允许暴露的配置段并以脱敏后的 JSON 返回
+func configSectionContents(uri, section string) ([]ResourceContents, error) {
+	if !configResourceSections[section] || !viper.IsSet(section) {
+		return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+	}
+
+	var value interface{} = viper.Get(section)
+	if m := viper.GetStringMap(section); len(m) > 0 {
+		value = redactConfig(m)
+	}
+	return jsonContents(uri, value)
+}
+
+// redactConfig 递归脱敏配置值，命中 DefaultRedactFields、configRedactFields 或以 _key 结尾的字段替换为 "***"
+func redactConfig(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			if isSensitiveConfigKey(k) {
+				out[k] = redactedValue
+				continue
+			}
+			out[k] = redactConfig(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = redactConfig(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// isSensitiveConfigKey 判断配置字段是否需要脱敏
+func isSensitiveConfigKey(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasSuffix(key, "_key") {
+		return true
+	}
+	for _, fields := range [][]string{DefaultRedactFields, configRedactFields} {
+		for _, f := range fields {
+			if key == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonContents 将值序列化为 JSON 资源内容
+func jsonContents(uri string, value interface{}) ([]ResourceContents, error) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return []ResourceContents{{URI: uri, MimeType: "application/json", Text: string(data)}}, nil
+}
+
+// tailFile 读取文件最后 n 行
+func tailFile(path string, n int) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("logging.file_path is not configured")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read log file: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+	"github.com/spf13/viper"
+)
+
+func TestConfigResources_NoCredentials(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile("../../config.yaml")
+	testutil.AssertNoError(t, viper.ReadInConfig())
+
+	// 默认配置中的凭据为空，填入可识别的值后检查它们不会出现在任何资源中
+	credentials := map[string]string{
+		"database.mysql.password": "mysql-password-sentinel",
+		"database.redis.password": "redis-password-sentinel",
+		"mcp.auth.jwt.secret":     "jwt-secret-sentinel",
+	}
+	for k, v := range credentials {
+		viper.Set(k, v)
+	}
+	viper.Set("mcp.auth.api_keys", []interface{}{
+		map[string]interface{}{"name": "ci-agent", "key": "api-key-sentinel"},
+	})
+	viper.Set("server.dsn", "dsn-sentinel")
+	viper.Set("server.signing_key", "signing-key-sentinel")
+	sentinels := []string{"api-key-sentinel", "dsn-sentinel", "signing-key-sentinel"}
+	for _, v := range credentials {
+		sentinels = append(sentinels, v)
+	}
+
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, registerConfigResources(a))
+
+	uris := []string{}
+	for _, r := range a.ListResources() {
+		uris = append(uris, r.URI)
+	}
+	for _, section := range []string{"project", "server", "environment", "database", "logging", "mcp"} {
+		uris = append(uris, "config://"+section)
+	}
+
+	for _, uri := range uris {
+		contents, err := a.ReadResource(context.Background(), uri)
+		if err != nil {
+			if !errors.Is(err, ErrResourceNotFound) {
+				t.Fatalf("%s: unexpected error %v", uri, err)
+			}
+			continue
+		}
+		for _, c := range contents {
+			for _, s := range sentinels {
+				if strings.Contains(c.Text, s) {
+					t.Errorf("%s leaks credential %q", uri, s)
+				}
+			}
+		}
+	}
+}
+
+func TestConfigSectionContents_Allowlist(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("server.port", 8080)
+	viper.Set("mcp.enabled", true)
+
+	_, err := configSectionContents("config://server", "server")
+	testutil.AssertNoError(t, err)
+
+	_, err = configSectionContents("config://mcp", "mcp")
+	testutil.AssertError(t, err)
+	testutil.AssertEqual(t, true, errors.Is(err, ErrResourceNotFound))
+}
+
+func TestRedactConfig(t *testing.T) {
+	got := redactConfig(map[string]interface{}{
+		"host":        "127.0.0.1",
+		"Password":    "p",
+		"dsn":         "root:p@tcp(db)/app",
+		"access_key":  "a",
+		"signing_key": "s",
+		"keys":        []interface{}{map[string]interface{}{"name": "n", "key": "k"}},
+	}).(map[string]interface{})
+
+	testutil.AssertEqual(t, "127.0.0.1", got["host"])
+	for _, k := range []string{"Password", "dsn", "access_key", "signing_key"} {
+		testutil.AssertEqual(t, redactedValue, got[k])
+	}
+	item := got["keys"].([]interface{})[0].(map[string]interface{})
+	testutil.AssertEqual(t, "n", item["name"])
+	testutil.AssertEqual(t, redactedValue, item["key"])
+}
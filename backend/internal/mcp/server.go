@@ -0,0 +1,558 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// LatestProtocolVersion 支持的最新 MCP 协议版本
+const LatestProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions 支持的 MCP 协议版本
+var supportedProtocolVersions = map[string]bool{
+	"2025-06-18": true,
+	"2025-03-26": true,
+	"2024-11-05": true,
+}
+
+// DefaultMaxSessions 默认的最大并发会话数
+const DefaultMaxSessions = 1000
+
+// DefaultSessionIdleTimeout 默认的会话空闲超时
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// ErrTooManySessions 会话数已达上限
+var ErrTooManySessions = errors.New("too many MCP sessions")
+
+// SessionLimits 会话限制，零值使用默认值
+type SessionLimits struct {
+	MaxSessions int           // 最大并发会话数，达到上限时拒绝 initialize
+	IdleTimeout time.Duration // 超过该时间没有请求的会话被关闭（连接通知流或有进行中的请求时不算空闲）
+}
+
+// maxSessions 最大并发会话数
+func (l SessionLimits) maxSessions() int {
+	if l.MaxSessions <= 0 {
+		return DefaultMaxSessions
+	}
+	return l.MaxSessions
+}
+
+// idleTimeout 会话空闲超时
+func (l SessionLimits) idleTimeout() time.Duration {
+	if l.IdleTimeout <= 0 {
+		return DefaultSessionIdleTimeout
+	}
+	return l.IdleTimeout
+}
+
+// Implementation 服务端/客户端实现信息
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// toolInfo tools/list 中的工具描述
+type toolInfo struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// Server MCP JSON-RPC 服务端，基于 MCPAdapter 分发协议方法，与具体传输方式无关
+type Server struct {
+	adapter MCPAdapter
+	info    Implementation
+
+	mu       sync.RWMutex
+	sessions map[string]*Session
+	limits   SessionLimits
+	batch    BatchLimits
+}
+
+// NewServer 创建 MCP 服务端
+func NewServer(adapter MCPAdapter, info Implementation) *Server {
+	return &Server{
+		adapter:  adapter,
+		info:     info,
+		sessions: make(map[string]*Session),
+	}
+}
+
+// Adapter 返回服务端使用的适配器
+func (s *Server) Adapter() MCPAdapter {
+	return s.adapter
+}
+
+// SetBatchLimits 设置 JSON-RPC 批量请求的限制
+func (s *Server) SetBatchLimits(limits BatchLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batch = limits
+}
+
+// BatchLimits 返回 JSON-RPC 批量请求的限制
+func (s *Server) BatchLimits() BatchLimits {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.batch
+}
+
+// SetSessionLimits 设置会话数上限与空闲超时
+func (s *Server) SetSessionLimits(limits SessionLimits) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limits = limits
+}
+
+// NewSession 创建客户端会话，会话会转发适配器的变更通知；会话数已达上限时返回 ErrTooManySessions
+func (s *Server) NewSession() (*Session, error) {
+	s.ExpireIdleSessions()
+
+	sess := &Session{
+		ID:            randomID(),
+		server:        s,
+		logLevel:      LogInfo,
+		subscriptions: make(map[string]bool),
+		inflight:      make(map[string]context.CancelFunc),
+		lastActive:    time.Now(),
+	}
+	sess.unsubscribe = s.adapter.Subscribe(sess.forward)
+
+	s.mu.Lock()
+	if len(s.sessions) >= s.limits.maxSessions() {
+		s.mu.Unlock()
+		sess.unsubscribe()
+		return nil, ErrTooManySessions
+	}
+	s.sessions[sess.ID] = sess
+	s.mu.Unlock()
+	return sess, nil
+}
+
+// Session 按 ID 查找会话并刷新其活跃时间，已空闲超时的会话会被关闭
+func (s *Server) Session(id string) (*Session, bool) {
+	s.mu.RLock()
+	sess, ok := s.sessions[id]
+	timeout := s.limits.idleTimeout()
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	if sess.idle(time.Now(), timeout) {
+		s.CloseSession(id)
+		return nil, false
+	}
+	sess.touch()
+	return sess, true
+}
+
+// ExpireIdleSessions 关闭所有空闲超时的会话，返回关闭的数量
+func (s *Server) ExpireIdleSessions() int {
+	now := time.Now()
+	var expired []*Session
+
+	s.mu.Lock()
+	timeout := s.limits.idleTimeout()
+	for id, sess := range s.sessions {
+		if sess.idle(now, timeout) {
+			expired = append(expired, sess)
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sess := range expired {
+		sess.close()
+	}
+	return len(expired)
+}
+
+// RunSessionJanitor 按间隔清理空闲超时的会话，直到 ctx 结束
+func (s *Server) RunSessionJanitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.ExpireIdleSessions()
+		}
+	}
+}
+
+// CloseSession 关闭会话并取消其所有进行中的请求
+func (s *Server) CloseSession(id string) {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	delete(s.sessions, id)
+	s.mu.Unlock()
+
+	if ok {
+		sess.close()
+	}
+}
+
+// Session MCP 客户端会话
+type Session struct {
+	ID string
+
+	server      *Server
+	unsubscribe func()
+
+	mu            sync.Mutex
+	initialized   bool
+	logLevel      LogLevel
+	subscriptions map[string]bool
+	inflight      map[string]context.CancelFunc
+	notifier      NotifyFunc
+	lastActive    time.Time
+}
+
+// SetNotifier 设置会话级通知通道（如 SSE 长连接），传入 nil 表示断开
+func (sess *Session) SetNotifier(fn NotifyFunc) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.notifier = fn
+	sess.lastActive = time.Now()
+}
+
+// touch 刷新会话的活跃时间
+func (sess *Session) touch() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.lastActive = time.Now()
+}
+
+// idle 会话是否已空闲超时：未连接通知流、没有进行中的请求且超过 timeout 没有活动
+func (sess *Session) idle(now time.Time, timeout time.Duration) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.notifier == nil && len(sess.inflight) == 0 && now.Sub(sess.lastActive) >= timeout
+}
+
+// notify 通过会话级通道发送通知，未连接时丢弃
+func (sess *Session) notify(n JSONRPCNotification) {
+	sess.mu.Lock()
+	fn := sess.notifier
+	sess.mu.Unlock()
+
+	if fn != nil {
+		fn(n)
+	}
+}
+
+// forward 转发适配器通知，资源更新仅发送给订阅了该 URI 的会话
+func (sess *Session) forward(n Notification) {
+	if n.Method == MethodResourcesUpdated {
+		uri, _ := n.Params["uri"].(string)
+		sess.mu.Lock()
+		subscribed := sess.subscriptions[uri]
+		sess.mu.Unlock()
+		if !subscribed {
+			return
+		}
+	}
+
+	var params interface{}
+	if n.Params != nil {
+		params = n.Params
+	}
+	sess.notify(JSONRPCNotification{JSONRPC: JSONRPCVersion, Method: n.Method, Params: params})
+}
+
+// shouldLog 判断日志级别是否达到客户端选择的级别
+func (sess *Session) shouldLog(level LogLevel) bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return logLevelSeverity[level] >= logLevelSeverity[sess.logLevel]
+}
+
+// close 释放会话资源
+func (sess *Session) close() {
+	if sess.unsubscribe != nil {
+		sess.unsubscribe()
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, cancel := range sess.inflight {
+		cancel()
+	}
+	sess.inflight = make(map[string]context.CancelFunc)
+	sess.notifier = nil
+}
+
+// Handle 处理单条 JSON-RPC 消息，out 用于发送本次请求相关的通知（进度、日志），可为 nil。
+// 通知消息以及被客户端取消的请求返回 nil。
+func (sess *Session) Handle(ctx context.Context, req *JSONRPCRequest, out NotifyFunc) *JSONRPCResponse {
+	if req.JSONRPC != JSONRPCVersion || req.Method == "" {
+		if req.IsNotification() {
+			return nil
+		}
+		return newErrorResponse(req.ID, NewJSONRPCError(CodeInvalidRequest, "invalid request"))
+	}
+
+	if req.IsNotification() {
+		sess.handleNotification(req)
+		return nil
+	}
+
+	// 注册进行中的请求，以便 notifications/cancelled 取消
+	ctx, cancel := context.WithCancel(ctx)
+	key := string(req.ID)
+	sess.mu.Lock()
+	sess.inflight[key] = cancel
+	sess.mu.Unlock()
+	defer func() {
+		sess.mu.Lock()
+		delete(sess.inflight, key)
+		sess.lastActive = time.Now()
+		sess.mu.Unlock()
+		cancel()
+	}()
+
+	scope := &requestScope{notify: out, session: sess}
+	var meta struct {
+		Meta struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if len(req.Params) > 0 && json.Unmarshal(req.Params, &meta) == nil {
+		scope.progressToken = meta.Meta.ProgressToken
+	}
+	ctx = withRequestScope(ctx, scope)
+
+	result, rpcErr := sess.dispatch(ctx, req)
+
+	// 客户端已取消的请求不再返回响应
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+	if rpcErr != nil {
+		return newErrorResponse(req.ID, rpcErr)
+	}
+	return newResult(req.ID, result)
+}
+
+// handleNotification 处理客户端通知
+func (sess *Session) handleNotification(req *JSONRPCRequest) {
+	switch req.Method {
+	case "notifications/initialized":
+		sess.mu.Lock()
+		sess.initialized = true
+		sess.mu.Unlock()
+	case MethodCancelled:
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if json.Unmarshal(req.Params, &params) != nil {
+			return
+		}
+		sess.mu.Lock()
+		cancel, ok := sess.inflight[string(params.RequestID)]
+		sess.mu.Unlock()
+		if ok {
+			cancel()
+		}
+	}
+}
+
+// dispatch 按方法分发请求
+func (sess *Session) dispatch(ctx context.Context, req *JSONRPCRequest) (interface{}, *JSONRPCError) {
+	adapter := sess.server.adapter
+
+	switch req.Method {
+	case "initialize":
+		return sess.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+
+	case "tools/list":
+		tools := adapter.ListTools()
+		infos := make([]toolInfo, 0, len(tools))
+		for _, tool := range tools {
+			infos = append(infos, newToolInfo(tool))
+		}
+		return map[string]interface{}{"tools": infos}, nil
+	case "tools/call":
+		var params struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		return sess.callTool(ctx, params.Name, params.Arguments)
+
+	case "resources/list":
+		return map[string]interface{}{"resources": adapter.ListResources()}, nil
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": adapter.ListResourceTemplates()}, nil
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		contents, err := adapter.ReadResource(ctx, params.URI)
+		if err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				return nil, &JSONRPCError{Code: CodeResourceNotFound, Message: err.Error(), Data: map[string]string{"uri": params.URI}}
+			}
+			return nil, NewJSONRPCError(CodeInternalError, err.Error())
+		}
+		return map[string]interface{}{"contents": contents}, nil
+	case "resources/subscribe", "resources/unsubscribe":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		sess.mu.Lock()
+		if req.Method == "resources/subscribe" {
+			sess.subscriptions[params.URI] = true
+		} else {
+			delete(sess.subscriptions, params.URI)
+		}
+		sess.mu.Unlock()
+		return struct{}{}, nil
+
+	case "prompts/list":
+		return map[string]interface{}{"prompts": adapter.ListPrompts()}, nil
+	case "prompts/get":
+		var params PromptRequest
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		result, err := adapter.GetPrompt(ctx, params.Name, params.Arguments)
+		if err != nil {
+			if errors.Is(err, ErrPromptNotFound) || errors.Is(err, ErrInvalidPromptArgs) {
+				return nil, NewJSONRPCError(CodeInvalidParams, err.Error())
+			}
+			return nil, NewJSONRPCError(CodeInternalError, err.Error())
+		}
+		return result, nil
+
+	case "logging/setLevel":
+		var params struct {
+			Level LogLevel `json:"level"`
+		}
+		if err := decodeParams(req.Params, &params); err != nil {
+			return nil, err
+		}
+		if !params.Level.Valid() {
+			return nil, NewJSONRPCError(CodeInvalidParams, "invalid log level: "+string(params.Level))
+		}
+		sess.mu.Lock()
+		sess.logLevel = params.Level
+		sess.mu.Unlock()
+		return struct{}{}, nil
+	}
+
+	return nil, NewJSONRPCError(CodeMethodNotFound, "method not found: "+req.Method)
+}
+
+// initialize 处理初始化握手
+func (sess *Session) initialize(raw json.RawMessage) (interface{}, *JSONRPCError) {
+	var params struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		ClientInfo      Implementation `json:"clientInfo"`
+	}
+	if err := decodeParams(raw, &params); err != nil {
+		return nil, err
+	}
+
+	version := params.ProtocolVersion
+	if !supportedProtocolVersions[version] {
+		version = LatestProtocolVersion
+	}
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]interface{}{"listChanged": true},
+			"resources": map[string]interface{}{"subscribe": true, "listChanged": true},
+			"prompts":   map[string]interface{}{"listChanged": true},
+			"logging":   map[string]interface{}{},
+		},
+		"serverInfo": sess.server.info,
+	}, nil
+}
+
+// callTool 调用工具，工具执行错误以 isError 结果返回，便于模型感知并自我纠正；限流以 JSON-RPC 错误返回并在 data.retryAfter 中给出重试秒数
+func (sess *Session) callTool(ctx context.Context, name string, args map[string]interface{}) (interface{}, *JSONRPCError) {
+	result, err := sess.server.adapter.CallTool(ctx, name, args)
+	if err != nil {
+		if errors.Is(err, ErrToolNotFound) {
+			return nil, NewJSONRPCError(CodeInvalidParams, "unknown tool: "+name)
+		}
+		if errors.Is(err, ErrInvalidArguments) {
+			return nil, NewJSONRPCError(CodeInvalidParams, err.Error())
+		}
+		var limitErr *RateLimitError
+		if errors.As(err, &limitErr) {
+			return nil, &JSONRPCError{Code: CodeRateLimited, Message: limitErr.Error(), Data: map[string]interface{}{
+				"scope":      limitErr.Scope,
+				"retryAfter": limitErr.RetryAfterSeconds(),
+			}}
+		}
+		return ErrorResult(err.Error()), nil
+	}
+
+	schema, _ := sess.server.adapter.GetTool(name)
+	wrapped, err := WrapResult(result, schema)
+	if err != nil {
+		return nil, NewJSONRPCError(CodeInternalError, err.Error())
+	}
+	return wrapped, nil
+}
+
+// newToolInfo 将 ToolSchema 转换为 MCP 协议的工具描述
+func newToolInfo(schema ToolSchema) toolInfo {
+	input := schema.Parameters
+	if input == nil {
+		input = map[string]interface{}{"type": "object"}
+	}
+	info := toolInfo{
+		Name:         schema.Name,
+		Description:  schema.Description,
+		InputSchema:  input,
+		OutputSchema: schema.OutputSchema,
+		Annotations:  schema.Annotations,
+	}
+	if schema.Annotations != nil {
+		info.Title = schema.Annotations.Title
+	}
+	return info
+}
+
+// decodeParams 解析请求参数
+func decodeParams(raw json.RawMessage, v interface{}) *JSONRPCError {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return NewJSONRPCError(CodeInvalidParams, "invalid params: "+err.Error())
+	}
+	return nil
+}
+
+// randomID 生成随机 ID（会话、审计记录等）
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,229 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func newRequest(id int, method string, params interface{}) *JSONRPCRequest {
+	req := &JSONRPCRequest{JSONRPC: JSONRPCVersion, Method: method}
+	if id > 0 {
+		req.ID, _ = json.Marshal(id)
+	}
+	if params != nil {
+		req.Params, _ = json.Marshal(params)
+	}
+	return req
+}
+
+// newSession 创建测试用的服务端会话
+func newSession(t *testing.T, a MCPAdapter) *Session {
+	t.Helper()
+	sess, err := NewServer(a, Implementation{Name: "test", Version: "1.0.0"}).NewSession()
+	testutil.AssertNoError(t, err)
+	return sess
+}
+
+// notificationRecorder 记录发送给客户端的通知
+type notificationRecorder struct {
+	mu    sync.Mutex
+	items []JSONRPCNotification
+}
+
+func (r *notificationRecorder) notify(n JSONRPCNotification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, n)
+}
+
+func (r *notificationRecorder) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var methods []string
+	for _, n := range r.items {
+		methods = append(methods, n.Method)
+	}
+	return methods
+}
+
+func TestServer_InitializeAndDispatch(t *testing.T) {
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, a.RegisterTool("echo", ToolSchema{}, echoHandler))
+	sess := newSession(t, a)
+	ctx := context.Background()
+
+	resp := sess.Handle(ctx, newRequest(1, "initialize", map[string]interface{}{"protocolVersion": "2025-03-26"}), nil)
+	testutil.AssertNil(t, resp.Error)
+	testutil.AssertEqual(t, resp.Result.(map[string]interface{})["protocolVersion"], "2025-03-26")
+	testutil.AssertNil(t, sess.Handle(ctx, newRequest(0, "notifications/initialized", nil), nil))
+
+	tests := []struct {
+		name     string
+		req      *JSONRPCRequest
+		wantCode int
+	}{
+		{name: "列出工具", req: newRequest(2, "tools/list", nil)},
+		{name: "调用工具", req: newRequest(3, "tools/call", map[string]interface{}{"name": "echo"})},
+		{name: "未知工具", req: newRequest(4, "tools/call", map[string]interface{}{"name": "missing"}), wantCode: CodeInvalidParams},
+		{name: "未知方法", req: newRequest(5, "foo/bar", nil), wantCode: CodeMethodNotFound},
+		{name: "非法日志级别", req: newRequest(6, "logging/setLevel", map[string]string{"level": "loud"}), wantCode: CodeInvalidParams},
+		{name: "读取不存在的资源", req: newRequest(7, "resources/read", map[string]string{"uri": "x://y"}), wantCode: CodeResourceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := sess.Handle(ctx, tt.req, nil)
+			if tt.wantCode != 0 {
+				testutil.AssertEqual(t, resp.Error.Code, tt.wantCode)
+			} else {
+				testutil.AssertNil(t, resp.Error)
+			}
+		})
+	}
+}
+
+func TestServer_StructuredTool(t *testing.T) {
+	a := NewMCPAdapter()
+	schema := ToolSchema{
+		OutputSchema: map[string]interface{}{"type": "object"},
+		Annotations:  &ToolAnnotations{Title: "统计", ReadOnlyHint: Hint(true)},
+	}
+	testutil.AssertNoError(t, a.RegisterTool("stats", schema, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return map[string]int{"count": 3}, nil
+	}))
+	sess := newSession(t, a)
+	ctx := context.Background()
+
+	list := sess.Handle(ctx, newRequest(1, "tools/list", nil), nil)
+	info := list.Result.(map[string]interface{})["tools"].([]toolInfo)[0]
+	testutil.AssertEqual(t, info.Title, "统计")
+	testutil.AssertEqual(t, *info.Annotations.ReadOnlyHint, true)
+	testutil.AssertEqual(t, info.OutputSchema, schema.OutputSchema)
+
+	call := sess.Handle(ctx, newRequest(2, "tools/call", map[string]interface{}{"name": "stats"}), nil)
+	result := call.Result.(*CallToolResult)
+	testutil.AssertEqual(t, result.StructuredContent, map[string]int{"count": 3})
+	testutil.AssertEqual(t, result.Content[0].Text, `{"count":3}`)
+}
+
+func TestServer_ProgressAndLogging(t *testing.T) {
+	a := NewMCPAdapter()
+	testutil.AssertNoError(t, a.RegisterTool("export", ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		for i := 1; i <= 3; i++ {
+			ReportProgress(ctx, float64(i), 3, "exporting")
+		}
+		Log(ctx, LogDebug, "export", "debug detail")
+		Log(ctx, LogWarning, "export", "slow query")
+		return "done", nil
+	}))
+	sess := newSession(t, a)
+
+	var rec notificationRecorder
+	params := map[string]interface{}{"name": "export", "_meta": map[string]interface{}{"progressToken": "p1"}}
+	resp := sess.Handle(context.Background(), newRequest(1, "tools/call", params), rec.notify)
+
+	testutil.AssertNil(t, resp.Error)
+	testutil.AssertEqual(t, rec.methods(), []string{MethodProgress, MethodProgress, MethodProgress, MethodLogMessage})
+
+	// 提高日志级别后 warning 日志也被过滤
+	rec = notificationRecorder{}
+	testutil.AssertNil(t, sess.Handle(context.Background(), newRequest(2, "logging/setLevel", map[string]string{"level": "error"}), nil).Error)
+	sess.Handle(context.Background(), newRequest(3, "tools/call", map[string]interface{}{"name": "export"}), rec.notify)
+	testutil.AssertEqual(t, len(rec.methods()), 0)
+}
+
+func TestServer_Cancellation(t *testing.T) {
+	a := NewMCPAdapter()
+	started := make(chan struct{})
+	testutil.AssertNoError(t, a.RegisterTool("long", ToolSchema{}, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	sess := newSession(t, a)
+
+	done := make(chan *JSONRPCResponse)
+	go func() {
+		done <- sess.Handle(context.Background(), newRequest(7, "tools/call", map[string]interface{}{"name": "long"}), nil)
+	}()
+
+	<-started
+	sess.Handle(context.Background(), newRequest(0, MethodCancelled, map[string]interface{}{"requestId": 7}), nil)
+
+	select {
+	case resp := <-done:
+		testutil.AssertNil(t, resp)
+	case <-time.After(time.Second):
+		t.Fatal("request was not cancelled")
+	}
+}
+
+func TestServer_ResourceSubscription(t *testing.T) {
+	a := NewMCPAdapter()
+	sess := newSession(t, a)
+
+	var rec notificationRecorder
+	sess.SetNotifier(rec.notify)
+
+	a.NotifyResourceUpdated("config://project")
+	testutil.AssertNil(t, sess.Handle(context.Background(), newRequest(1, "resources/subscribe", map[string]string{"uri": "config://project"}), nil).Error)
+	a.NotifyResourceUpdated("config://project")
+	a.NotifyResourceUpdated("config://other")
+
+	testutil.AssertEqual(t, rec.methods(), []string{MethodResourcesUpdated})
+}
+
+func TestServer_SessionLimits(t *testing.T) {
+	a := NewMCPAdapter()
+	server := NewServer(a, Implementation{Name: "test"})
+	server.SetSessionLimits(SessionLimits{MaxSessions: 2, IdleTimeout: time.Minute})
+
+	first, err := server.NewSession()
+	testutil.AssertNoError(t, err)
+	second, err := server.NewSession()
+	testutil.AssertNoError(t, err)
+
+	t.Run("达到上限时拒绝", func(t *testing.T) {
+		_, err := server.NewSession()
+		testutil.AssertEqual(t, errors.Is(err, ErrTooManySessions), true)
+	})
+
+	t.Run("连接通知流的会话不过期", func(t *testing.T) {
+		rec := &notificationRecorder{}
+		second.SetNotifier(rec.notify)
+		second.lastActive = time.Now().Add(-time.Hour)
+		testutil.AssertEqual(t, server.ExpireIdleSessions(), 0)
+		second.SetNotifier(nil)
+	})
+
+	t.Run("空闲会话过期并取消订阅", func(t *testing.T) {
+		first.lastActive = time.Now().Add(-2 * time.Minute)
+		testutil.AssertEqual(t, server.ExpireIdleSessions(), 1)
+		_, ok := server.Session(first.ID)
+		testutil.AssertEqual(t, ok, false)
+
+		// 过期会话不再接收适配器通知
+		rec, live := &notificationRecorder{}, &notificationRecorder{}
+		first.SetNotifier(rec.notify)
+		second.SetNotifier(live.notify)
+		testutil.AssertNoError(t, a.RegisterTool("late", ToolSchema{}, echoHandler))
+		second.SetNotifier(nil)
+		testutil.AssertEqual(t, len(rec.methods()), 0)
+		testutil.AssertEqual(t, len(live.methods()) > 0, true)
+
+		// 释放名额后可以创建新会话
+		_, err := server.NewSession()
+		testutil.AssertNoError(t, err)
+	})
+
+	t.Run("查询时关闭已超时的会话", func(t *testing.T) {
+		second.lastActive = time.Now().Add(-2 * time.Minute)
+		_, ok := server.Session(second.ID)
+		testutil.AssertEqual(t, ok, false)
+	})
+}
@@ -35,6 +35,12 @@ func registerHealthTool(adapter MCPAdapter) error {
 			"properties": map[string]interface{}{},
 			"required":   []string{},
 		},
+		Annotations: &ToolAnnotations{
+			Title:          "健康检查",
+			ReadOnlyHint:   Hint(true),
+			IdempotentHint: Hint(true),
+		},
+		Scopes: []string{HealthReadScope},
 	}
 
 	handler := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
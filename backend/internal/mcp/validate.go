@@ -0,0 +1,173 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// validateArguments 按工具的 inputSchema 校验参数：必填字段与类型（含嵌套对象与数组元素），
+// 只支持 type、properties、required、items 关键字，其余关键字忽略；不匹配时返回 ErrInvalidArguments
+func validateArguments(schema map[string]interface{}, args map[string]interface{}) error {
+	if schema == nil {
+		return nil
+	}
+	var value interface{} = args
+	if args == nil {
+		value = map[string]interface{}{}
+	}
+	if err := validateValue(schema, value, ""); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArguments, err)
+	}
+	return nil
+}
+
+// validateValue 校验单个值，path 为字段路径（用于错误信息）
+func validateValue(schema map[string]interface{}, value interface{}, path string) error {
+	if types := schemaTypes(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, typ := range types {
+			if matchesType(typ, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: expected %s, got %s", fieldName(path), strings.Join(types, " or "), jsonType(value))
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: missing required field", fieldName(joinPath(path, name)))
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := properties[name].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := validateValue(prop, v[name], joinPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range v {
+			if err := validateValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypes 读取 type 关键字，支持字符串与字符串数组
+func schemaTypes(v interface{}) []string {
+	if s, ok := v.(string); ok {
+		return []string{s}
+	}
+	return schemaStrings(v)
+}
+
+// schemaStrings 读取字符串数组（Go 代码中为 []string，JSON 解析后为 []interface{}）
+func schemaStrings(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// matchesType 值是否符合 JSON Schema 类型，未知类型视为匹配；Go 代码传入的 map 与切片按 object、array 处理
+func matchesType(typ string, value interface{}) bool {
+	switch typ {
+	case "null":
+		return value == nil
+	case "object":
+		return value != nil && reflect.TypeOf(value).Kind() == reflect.Map
+	case "array":
+		if value == nil {
+			return false
+		}
+		kind := reflect.TypeOf(value).Kind()
+		return kind == reflect.Slice || kind == reflect.Array
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		f, ok := toFloat(value)
+		return ok && f == math.Trunc(f)
+	}
+	return true
+}
+
+// toFloat 将数值（JSON 解析的 float64 或 Go 代码传入的整数、浮点数）转换为 float64
+func toFloat(value interface{}) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// jsonType 值对应的 JSON 类型名
+func jsonType(value interface{}) string {
+	for _, typ := range []string{"null", "object", "array", "string", "boolean", "number"} {
+		if matchesType(typ, value) {
+			return typ
+		}
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// fieldName 错误信息中的字段名，顶层为 arguments
+func fieldName(path string) string {
+	if path == "" {
+		return "arguments"
+	}
+	return path
+}
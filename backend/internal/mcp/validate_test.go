@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+func TestValidateArguments(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name":  map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{"type": "integer"},
+			"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"user": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"age": map[string]interface{}{"type": []interface{}{"number", "null"}}},
+				"required":   []interface{}{"age"},
+			},
+		},
+		"required": []string{"name"},
+	}
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr bool
+	}{
+		{name: "合法", args: map[string]interface{}{"name": "a", "count": float64(2), "tags": []interface{}{"x"}, "user": map[string]interface{}{"age": nil}}},
+		{name: "Go 类型参数", args: map[string]interface{}{"name": "a", "count": 2, "tags": []string{"x"}}},
+		{name: "未声明的字段不校验", args: map[string]interface{}{"name": "a", "extra": true}},
+		{name: "缺少必填字段", args: map[string]interface{}{"count": float64(1)}, wantErr: true},
+		{name: "参数为空", args: nil, wantErr: true},
+		{name: "类型错误", args: map[string]interface{}{"name": float64(1)}, wantErr: true},
+		{name: "整数带小数", args: map[string]interface{}{"name": "a", "count": 1.5}, wantErr: true},
+		{name: "数组元素类型错误", args: map[string]interface{}{"name": "a", "tags": []interface{}{"x", float64(1)}}, wantErr: true},
+		{name: "嵌套对象缺少必填字段", args: map[string]interface{}{"name": "a", "user": map[string]interface{}{}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateArguments(schema, tt.args)
+			if !tt.wantErr {
+				testutil.AssertNoError(t, err)
+				return
+			}
+			testutil.AssertEqual(t, errors.Is(err, ErrInvalidArguments), true)
+			testutil.AssertEqual(t, errors.Is(err, ErrInvalidParams), true)
+		})
+	}
+}
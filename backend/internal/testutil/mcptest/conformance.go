@@ -0,0 +1,180 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+	"github.com/richer/ai_skeleton/internal/testutil"
+)
+
+// toolNamePattern MCP 规范建议的工具名字符集
+var toolNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,128}$`)
+
+// RunConformance 对适配器运行 MCP 一致性测试：握手、JSON-RPC 错误码（含参数不符合 inputSchema）、工具 schema 与通知行为。
+// newAdapter 每个子测试调用一次，应返回注册好工具/资源/提示词的新适配器。
+func RunConformance(t *testing.T, newAdapter func() mcp.MCPAdapter) {
+	t.Run("握手", func(t *testing.T) {
+		c := NewClient(t, newAdapter())
+		testutil.AssertEqual(t, c.ServerInfo().Name != "", true)
+
+		resp := c.Request(t, 1, "initialize", map[string]interface{}{"protocolVersion": "1999-01-01"})
+		testutil.AssertNil(t, resp.Error)
+		var result struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		testutil.AssertNoError(t, json.Unmarshal(resp.Result, &result))
+		testutil.AssertEqual(t, result.ProtocolVersion, mcp.LatestProtocolVersion)
+
+		testutil.AssertNoError(t, c.Ping(context.Background()))
+	})
+
+	t.Run("错误码", func(t *testing.T) {
+		adapter := newAdapter()
+		typed := mcp.ToolSchema{
+			Parameters: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"count": map[string]interface{}{"type": "integer"}},
+				"required":   []string{"count"},
+			},
+		}
+		testutil.AssertNoError(t, adapter.RegisterTool("mcptest_typed", typed, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "ok", nil
+		}))
+		c := NewClient(t, adapter)
+		tests := []struct {
+			name     string
+			req      *mcp.JSONRPCRequest
+			wantCode int
+		}{
+			{
+				name:     "错误的协议版本",
+				req:      &mcp.JSONRPCRequest{JSONRPC: "1.0", ID: json.RawMessage("1"), Method: "ping"},
+				wantCode: mcp.CodeInvalidRequest,
+			},
+			{
+				name:     "缺少方法",
+				req:      &mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPCVersion, ID: json.RawMessage("2")},
+				wantCode: mcp.CodeInvalidRequest,
+			},
+			{
+				name:     "未知方法",
+				req:      rawRequest(3, "foo/bar", nil),
+				wantCode: mcp.CodeMethodNotFound,
+			},
+			{
+				name:     "未知工具",
+				req:      rawRequest(4, "tools/call", map[string]interface{}{"name": "mcptest_missing_tool"}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+			{
+				name:     "参数类型错误",
+				req:      rawRequest(5, "tools/call", map[string]interface{}{"name": 1}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+			{
+				name:     "缺少必填参数",
+				req:      rawRequest(9, "tools/call", map[string]interface{}{"name": "mcptest_typed", "arguments": map[string]interface{}{}}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+			{
+				name:     "参数不符合 inputSchema 类型",
+				req:      rawRequest(10, "tools/call", map[string]interface{}{"name": "mcptest_typed", "arguments": map[string]interface{}{"count": "three"}}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+			{
+				name:     "未知提示词",
+				req:      rawRequest(6, "prompts/get", map[string]interface{}{"name": "mcptest_missing_prompt"}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+			{
+				name:     "资源不存在",
+				req:      rawRequest(7, "resources/read", map[string]interface{}{"uri": "mcptest://missing"}),
+				wantCode: mcp.CodeResourceNotFound,
+			},
+			{
+				name:     "非法日志级别",
+				req:      rawRequest(8, "logging/setLevel", map[string]interface{}{"level": "loud"}),
+				wantCode: mcp.CodeInvalidParams,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := c.Send(t, tt.req)
+				if resp == nil || resp.Error == nil {
+					t.Fatalf("expected error %d, got %+v", tt.wantCode, resp)
+				}
+				testutil.AssertEqual(t, resp.Error.Code, tt.wantCode)
+				if len(tt.req.ID) > 0 && tt.wantCode != mcp.CodeInvalidRequest {
+					testutil.AssertEqual(t, string(resp.ID), string(tt.req.ID))
+				}
+			})
+		}
+	})
+
+	t.Run("工具schema", func(t *testing.T) {
+		c := NewClient(t, newAdapter())
+		tools := c.ListTools(t)
+
+		names := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+			t.Run(tool.Name, func(t *testing.T) {
+				testutil.AssertEqual(t, toolNamePattern.MatchString(tool.Name), true)
+				assertObjectSchema(t, tool.InputSchema)
+				if tool.OutputSchema != nil {
+					assertObjectSchema(t, tool.OutputSchema)
+				}
+			})
+		}
+		testutil.AssertEqual(t, sort.StringsAreSorted(names), true)
+	})
+
+	t.Run("通知", func(t *testing.T) {
+		adapter := newAdapter()
+		c := NewClient(t, adapter)
+
+		// 通知不返回响应，取消未知请求被忽略
+		testutil.AssertNil(t, c.Request(t, 0, "notifications/initialized", nil))
+		testutil.AssertNil(t, c.Request(t, 0, "notifications/cancelled", map[string]interface{}{"requestId": 999}))
+
+		probe := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "ok", nil
+		}
+		testutil.AssertNoError(t, adapter.RegisterTool("mcptest_probe", mcp.ToolSchema{}, probe))
+		c.WaitNotification(t, mcp.MethodToolsListChanged, time.Second)
+
+		result := c.CallTool(t, "mcptest_probe", nil)
+		testutil.AssertEqual(t, result.Content, []mcp.Content{mcp.TextContent("ok")})
+	})
+}
+
+// rawRequest 构造 JSON-RPC 请求
+func rawRequest(id int, method string, params interface{}) *mcp.JSONRPCRequest {
+	req := &mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPCVersion, Method: method}
+	req.ID, _ = json.Marshal(id)
+	if params != nil {
+		req.Params, _ = json.Marshal(params)
+	}
+	return req
+}
+
+// assertObjectSchema 断言 schema 为 object 类型且 required 中的字段均在 properties 中声明
+func assertObjectSchema(t *testing.T, schema map[string]interface{}) {
+	t.Helper()
+	testutil.AssertEqual(t, schema["type"], "object")
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]interface{})
+	for _, r := range required {
+		name, _ := r.(string)
+		if _, ok := properties[name]; !ok {
+			t.Errorf("required field %q is not declared in properties", name)
+		}
+	}
+}
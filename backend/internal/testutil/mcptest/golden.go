@@ -0,0 +1,48 @@
+package mcptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update 使用 go test ./... -update 重新生成 golden 文件
+var update = flag.Bool("update", false, "update MCP golden files")
+
+// AssertGolden 将 v 序列化为缩进 JSON 并与 golden 文件比较
+func AssertGolden(t *testing.T, file string, v interface{}) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal golden value: %v", err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatalf("failed to create golden dir: %v", err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch (run with -update to accept)\ngot:\n%s\nwant:\n%s", file, got, want)
+	}
+}
+
+// AssertToolsGolden 比较 tools/list 输出与 golden 文件
+func (c *Client) AssertToolsGolden(t *testing.T, file string) {
+	t.Helper()
+	AssertGolden(t, file, c.ListTools(t))
+}
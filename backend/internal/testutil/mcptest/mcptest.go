@@ -0,0 +1,169 @@
+// Package mcptest 进程内 MCP 测试客户端：通过内存传输连接适配器，完成握手并提供调用、通知与 golden 文件断言。
+// 独立于 testutil 包，避免 testutil → mcp → service 的导入环。
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/richer/ai_skeleton/internal/mcp"
+	"github.com/richer/ai_skeleton/internal/mcp/client"
+)
+
+// Client 进程内 MCP 测试客户端
+type Client struct {
+	*client.Client
+
+	session   *mcp.Session
+	transport *memoryTransport
+}
+
+// NewClient 基于适配器创建服务端会话并完成 initialize 握手，测试结束时自动关闭
+func NewClient(t *testing.T, adapter mcp.MCPAdapter) *Client {
+	t.Helper()
+
+	server := mcp.NewServer(adapter, mcp.Implementation{Name: "mcptest", Version: "0.0.0"})
+	sess, err := server.NewSession()
+	if err != nil {
+		t.Fatalf("mcp session failed: %v", err)
+	}
+	transport := &memoryTransport{session: sess}
+	sess.SetNotifier(transport.record)
+
+	c := &Client{
+		Client:    client.New(transport, mcp.Implementation{Name: "mcptest-client", Version: "0.0.0"}),
+		session:   sess,
+		transport: transport,
+	}
+	if err := c.Initialize(context.Background()); err != nil {
+		t.Fatalf("mcp initialize failed: %v", err)
+	}
+	t.Cleanup(func() { server.CloseSession(sess.ID) })
+	return c
+}
+
+// CallTool 调用工具，传输或协议错误时测试失败
+func (c *Client) CallTool(t *testing.T, name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	result, err := c.Client.CallTool(context.Background(), name, args)
+	if err != nil {
+		t.Fatalf("call tool %s failed: %v", name, err)
+	}
+	return result
+}
+
+// ListTools 列出工具，出错时测试失败
+func (c *Client) ListTools(t *testing.T) []client.Tool {
+	t.Helper()
+	tools, err := c.Client.ListTools(context.Background())
+	if err != nil {
+		t.Fatalf("list tools failed: %v", err)
+	}
+	return tools
+}
+
+// Request 发送原始 JSON-RPC 请求，返回原始响应（通知返回 nil），用于断言错误码
+func (c *Client) Request(t *testing.T, id int, method string, params interface{}) *client.Response {
+	t.Helper()
+	req := &mcp.JSONRPCRequest{JSONRPC: mcp.JSONRPCVersion, Method: method}
+	if id > 0 {
+		req.ID, _ = json.Marshal(id)
+	}
+	if params != nil {
+		raw, err := json.Marshal(params)
+		if err != nil {
+			t.Fatalf("invalid params: %v", err)
+		}
+		req.Params = raw
+	}
+	return c.Send(t, req)
+}
+
+// Send 发送任意 JSON-RPC 消息（可构造非法消息）
+func (c *Client) Send(t *testing.T, req *mcp.JSONRPCRequest) *client.Response {
+	t.Helper()
+	resp, err := c.transport.RoundTrip(context.Background(), req)
+	if err != nil {
+		t.Fatalf("%s failed: %v", req.Method, err)
+	}
+	return resp
+}
+
+// Notifications 返回目前收到的所有通知（请求级与会话级）
+func (c *Client) Notifications() []mcp.JSONRPCNotification {
+	return c.transport.notifications()
+}
+
+// WaitNotification 等待指定方法的通知，超时后测试失败
+func (c *Client) WaitNotification(t *testing.T, method string, timeout time.Duration) mcp.JSONRPCNotification {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		for _, n := range c.Notifications() {
+			if n.Method == method {
+				return n
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("notification %s not received within %s", method, timeout)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// memoryTransport 内存传输：消息经过 JSON 编解码后直接交给会话处理，确保序列化行为与真实传输一致
+type memoryTransport struct {
+	session *mcp.Session
+
+	mu       sync.Mutex
+	received []mcp.JSONRPCNotification
+}
+
+// RoundTrip 处理一条消息
+func (t *memoryTransport) RoundTrip(ctx context.Context, req *mcp.JSONRPCRequest) (*client.Response, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	var decoded mcp.JSONRPCRequest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+
+	resp := t.session.Handle(ctx, &decoded, t.record)
+	if resp == nil {
+		return nil, nil
+	}
+
+	data, err = json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var out client.Response
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Close 内存传输无需释放资源
+func (t *memoryTransport) Close() error {
+	return nil
+}
+
+// record 记录通知
+func (t *memoryTransport) record(n mcp.JSONRPCNotification) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.received = append(t.received, n)
+}
+
+// notifications 返回通知副本
+func (t *memoryTransport) notifications() []mcp.JSONRPCNotification {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]mcp.JSONRPCNotification(nil), t.received...)
+}
@@ -2,7 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/manifoldco/promptui"
+	"github.com/richer/ai_skeleton/cli/internal/generator"
 	"github.com/spf13/cobra"
 )
 
@@ -39,11 +44,125 @@ var generateServiceCmd = &cobra.Command{
 	},
 }
 
+var (
+	mcpToolBackend  string
+	mcpToolMethods  []string
+	mcpToolWithTest bool
+)
+
+var generateMCPToolCmd = &cobra.Command{
+	Use:   "mcp-tool [包名.接口名]",
+	Short: "将服务方法包装为 MCP 工具",
+	Long: `解析 internal/service 下的服务接口，为选中的方法生成 registerXTool 函数，
+参数 schema 由方法参数（或参数结构体的 json tag）推导，并自动在 mcp.RegisterAllTools 中注册。
+
+示例：
+  ais generate mcp-tool health.HealthService
+  ais gen mcp-tool user.UserService --methods Get,List`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGenerateMCPTool,
+}
+
 func init() {
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.AddCommand(generateServiceCmd)
+	generateCmd.AddCommand(generateMCPToolCmd)
+
+	generateMCPToolCmd.Flags().StringVar(&mcpToolBackend, "backend", "", "后端目录（默认自动查找包含 go.mod 的 backend 目录）")
+	generateMCPToolCmd.Flags().StringSliceVar(&mcpToolMethods, "methods", nil, "要生成的方法（逗号分隔），不指定时交互式选择")
+	generateMCPToolCmd.Flags().BoolVar(&mcpToolWithTest, "withtest", true, "生成测试文件")
 
 	generateServiceCmd.Flags().BoolVar(&withAPI, "withapi", true, "生成 API 层")
 	generateServiceCmd.Flags().BoolVar(&withMCP, "withmcp", false, "注册 MCP 工具")
 	generateServiceCmd.Flags().BoolVar(&withTest, "withtest", true, "生成测试文件")
 }
+
+func runGenerateMCPTool(cmd *cobra.Command, args []string) error {
+	backendDir, err := resolveBackendDir(mcpToolBackend)
+	if err != nil {
+		return err
+	}
+
+	svc, err := generator.ParseService(backendDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	methods := mcpToolMethods
+	if len(methods) == 0 {
+		methods, err = promptMethods(svc)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("📦 生成 MCP 工具: %s\n", args[0])
+	result, err := generator.GenerateMCPTool(svc, generator.MCPToolOptions{
+		BackendDir: backendDir,
+		Methods:    methods,
+		WithTest:   mcpToolWithTest,
+	})
+	if err != nil {
+		return fmt.Errorf("生成 MCP 工具失败: %w", err)
+	}
+
+	for _, tool := range result.Tools {
+		fmt.Printf("  ✓ %s\n", tool)
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Printf("  ⚠️  跳过 %s\n", skipped)
+	}
+	for _, file := range result.Files {
+		fmt.Printf("  - 写入 %s\n", file)
+	}
+	if len(result.Tools) == 0 {
+		fmt.Println("没有生成任何工具")
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("✅ 生成完成！下一步：")
+	fmt.Println("  1. 检查生成的 schema 描述与测试参数")
+	fmt.Println("  2. 更新工具列表快照：cd backend && go test ./internal/mcp/ -update")
+	return nil
+}
+
+// resolveBackendDir 确定后端目录：优先使用参数，否则依次尝试 ./backend 与当前目录
+func resolveBackendDir(dir string) (string, error) {
+	candidates := []string{"backend", "."}
+	if dir != "" {
+		candidates = []string{dir}
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(filepath.Join(c, "go.mod")); err == nil {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("未找到后端目录（包含 go.mod），请通过 --backend 指定")
+}
+
+// promptMethods 交互式选择方法，留空表示全部
+func promptMethods(svc *generator.ServiceInfo) ([]string, error) {
+	fmt.Printf("接口 %s.%s 的方法：\n", svc.Package, svc.Interface)
+	for _, m := range svc.Methods {
+		note := m.Doc
+		if m.Unsupported != "" {
+			note = "不支持：" + m.Unsupported
+		}
+		fmt.Printf("  - %s  %s\n", m.Name, note)
+	}
+
+	prompt := promptui.Prompt{Label: "选择方法（逗号分隔，留空表示全部）"}
+	input, err := prompt.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []string
+	for _, name := range strings.Split(input, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			methods = append(methods, name)
+		}
+	}
+	return methods, nil
+}
@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/richer/ai_skeleton/cli/internal/installer"
 	"github.com/richer/ai_skeleton/cli/internal/renderer"
@@ -14,9 +21,31 @@ var (
 	projectVersion string
 	modulePath     string
 	templateURL    string
+	templateDir    string
+	templateZip    string
+	offline        bool
+	templateRef    string
+	templateSHA256 string
+	noCache        bool
+	templateToken  string
+	proxyURL       string
+	caCert         string
+	timeout        time.Duration
+	retries        int
+	features       []string
+	nonInteractive bool
+	answersFile    string
+	jsonOutput     bool
+	dryRun         bool
+	force          bool
+	merge          bool
 	skipDeps       bool
 	skipNpm        bool
 	skipGo         bool
+	skipGit        bool
+	skipSwag       bool
+	skipHooks      bool
+	verify         bool
 )
 
 var initCmd = &cobra.Command{
@@ -25,15 +54,39 @@ var initCmd = &cobra.Command{
 	Long: `初始化一个新的 AI Skeleton 项目。
 
 此命令会从远程模板仓库下载最新模板并创建项目。
-默认使用官方模板仓库，可通过 --template-url 指定私有仓库。
+默认使用官方模板仓库，可通过 --template-url 指定私有仓库；
+无法访问外网时可通过 --template-dir、--template-zip 使用本地模板，
+或通过 --offline 使用 CLI 内嵌的模板快照。
+远程模板缓存在 ~/.cache/ais/templates，再次初始化时通过 ETag 校验是否更新；
+可通过 --template-ref 固定版本、--template-sha256 校验归档。
+--template-url 支持简写 gh:org/repo、gl:group/repo、gitea:org/repo（可加 @ref 指定版本，
+自建实例在仓库前加主机，如 gl:git.example.com/group/repo）。私有仓库通过 --token、
+环境变量 AIS_TEMPLATE_TOKEN 或 ~/.netrc 认证；--proxy、--ca-cert 用于代理与自签名证书，
+下载失败时按 --retries 重试。
+模板清单声明了可选功能时，可通过 --features 选择（如 --features mysql,mcp），
+未指定时逐个确认；未启用功能的文件、配置段、依赖与路由不会生成。
+
+在 CI 等无法交互的环境中使用 --yes（或 --non-interactive）：缺少项目名称或
+必填的模板变量时直接报错，其余使用默认值；--answers 从 YAML 文件读取项目信息、
+功能与模板变量（命令行参数优先）；--json 将最终的项目信息以 JSON 输出到标准输出，
+进度信息输出到标准错误。
+
+--dry-run 只获取并渲染模板，列出将创建、渲染（内容有替换）与跳过的文件及将执行的钩子，
+并以统一 diff 显示替换前后变化的行，不写入任何文件，可用于审查自定义模板。
+--template-url 指定的模板声明了钩子时，执行前列出命令并确认（--yes 时直接执行）。
+
+生成过程先写入目标目录旁的临时目录，全部成功后才移动到目标位置；失败或按下
+Ctrl-C 时会清理临时文件，不会留下生成一半的项目。目标目录已存在且不为空时
+默认报错，--merge 合并到已有目录并保留同名文件，--force 覆盖同名文件。
 
 此命令会：
-1. 检查环境依赖（Go、npm）
-2. 收集项目信息
-3. 从远程下载最新模板
+1. 收集项目信息
+2. 检查环境依赖（Go、npm）
+3. 获取模板（远程仓库或本地模板）
 4. 替换模板中的占位符
 5. 安装依赖（Air、Swagger、npm packages）
-6. 生成初始代码`,
+6. 执行生成后步骤：swag init、模板钩子、git init 与初始提交、
+   --verify 时执行 go build 与 npm run build；某一步失败时给出提示并继续后续步骤`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -45,31 +98,65 @@ func init() {
 	initCmd.Flags().StringVarP(&projectDesc, "desc", "d", "", "项目描述")
 	initCmd.Flags().StringVarP(&projectVersion, "version", "v", "1.0.0", "项目版本")
 	initCmd.Flags().StringVarP(&modulePath, "module", "m", "", "Go 模块路径")
-	initCmd.Flags().StringVarP(&templateURL, "template-url", "t", "", "自定义模板仓库地址（用于私有仓库）")
+	initCmd.Flags().StringVarP(&templateURL, "template-url", "t", "", "自定义模板仓库地址，支持 gh:org/repo@ref 等简写（用于私有仓库）")
+	initCmd.Flags().StringVar(&templateDir, "template-dir", "", "使用本地模板目录（离线）")
+	initCmd.Flags().StringVar(&templateZip, "template-zip", "", "使用本地模板 ZIP 文件（离线）")
+	initCmd.Flags().BoolVar(&offline, "offline", false, "使用 CLI 内嵌的模板快照（离线）")
+	initCmd.Flags().StringVar(&templateRef, "template-ref", "", "远程模板版本（tag、分支或 commit，如 v1.2.0）")
+	initCmd.Flags().StringVar(&templateSHA256, "template-sha256", "", "模板归档的 SHA-256 校验和（远程模板或 --template-zip）")
+	initCmd.Flags().BoolVar(&noCache, "no-cache", false, "不使用模板缓存，总是重新下载")
+	initCmd.Flags().StringVar(&templateToken, "token", "", "私有模板仓库的访问令牌（默认读取 AIS_TEMPLATE_TOKEN 或 ~/.netrc）")
+	initCmd.Flags().StringVar(&proxyURL, "proxy", "", "下载模板使用的代理（默认读取 HTTPS_PROXY 等环境变量）")
+	initCmd.Flags().StringVar(&caCert, "ca-cert", "", "额外信任的 CA 证书文件（PEM），用于自签名证书的私有仓库")
+	initCmd.Flags().DurationVar(&timeout, "timeout", 10*time.Minute, "下载模板的超时时间")
+	initCmd.Flags().IntVar(&retries, "retries", 3, "下载模板失败时的重试次数")
+	initCmd.MarkFlagsMutuallyExclusive("template-url", "template-dir", "template-zip", "offline")
+	initCmd.Flags().StringSliceVar(&features, "features", nil, "启用的可选功能（如 mysql,redis,auth,mcp,docker,frontend），为空表示不启用")
+	initCmd.Flags().BoolVarP(&nonInteractive, "yes", "y", false, "非交互模式：使用默认值，缺少必填项时直接报错")
+	initCmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "同 --yes")
+	initCmd.Flags().StringVar(&answersFile, "answers", "", "从 YAML 答案文件读取项目信息（name、description、version、module、features、vars）")
+	initCmd.Flags().BoolVar(&jsonOutput, "json", false, "以 JSON 输出项目信息（进度信息输出到标准错误）")
+	initCmd.Flags().BoolVar(&dryRun, "dry-run", false, "只显示生成计划与替换 diff，不写入任何文件")
+	initCmd.Flags().BoolVar(&merge, "merge", false, "目标目录已存在时合并生成，保留同名的已有文件")
+	initCmd.Flags().BoolVar(&force, "force", false, "目标目录已存在时合并生成，覆盖同名的已有文件")
+	initCmd.MarkFlagsMutuallyExclusive("merge", "force")
 	initCmd.Flags().BoolVar(&skipDeps, "skipdeps", false, "跳过依赖安装")
 	initCmd.Flags().BoolVar(&skipNpm, "skipnpm", false, "跳过 npm 依赖安装")
 	initCmd.Flags().BoolVar(&skipGo, "skipgo", false, "跳过 Go 工具安装")
+	initCmd.Flags().BoolVar(&skipGit, "skipgit", false, "跳过 git init 与初始提交")
+	initCmd.Flags().BoolVar(&skipSwag, "skipswag", false, "跳过 swag init")
+	initCmd.Flags().BoolVar(&skipHooks, "skiphooks", false, "跳过模板清单声明的钩子")
+	initCmd.Flags().BoolVar(&verify, "verify", false, "生成后执行 go build 与 npm run build 检查项目能否构建")
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	// 环境检查
-	fmt.Println("🔍 检查环境依赖...")
-	if err := installer.CheckEnvironment(); err != nil {
-		return err
+	// --json 时标准输出只保留 JSON，进度与提示改为输出到标准错误
+	stdout := os.Stdout
+	if jsonOutput {
+		os.Stdout = os.Stderr
+		defer func() { os.Stdout = stdout }()
 	}
-	fmt.Println()
 
 	// 收集项目信息
 	if len(args) > 0 && projectName == "" {
 		projectName = args[0]
 	}
 
-	meta, err := collectProjectInfo()
+	meta, err := collectProjectInfo(cmd)
 	if err != nil {
 		return err
 	}
-
 	fmt.Println()
+
+	// 环境检查（已确定不生成前端时无需 npm；--dry-run 不需要）
+	if !dryRun {
+		fmt.Println("🔍 检查环境依赖...")
+		if err := installer.CheckEnvironment(meta.HasFeature("frontend")); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
 	fmt.Println("📝 项目信息：")
 	fmt.Printf("  名称：%s\n", meta.Name)
 	fmt.Printf("  描述：%s\n", meta.Description)
@@ -84,6 +171,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
+	if dryRun {
+		fmt.Println("✅ 预览完成，未写入任何文件")
+		return printJSON(stdout, meta)
+	}
+
 	// 安装依赖
 	if !skipDeps {
 		fmt.Println("📥 安装依赖...")
@@ -96,7 +188,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		if !skipNpm {
+		if !skipNpm && meta.HasFeature("frontend") {
 			if err := installer.InstallNpmDeps(meta.Name); err != nil {
 				fmt.Printf("  ⚠️  npm 依赖安装失败: %v\n", err)
 			} else {
@@ -106,26 +198,144 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	// 远程模板的钩子需 --yes 或交互式确认后才执行
+	if !skipHooks && !renderer.ConfirmHooks(meta) {
+		fmt.Println("  ⚠️  未确认执行模板钩子，已跳过")
+		skipHooks = true
+	}
+
+	// 生成后步骤：失败时提示并继续
+	if steps := postGenerateSteps(meta); len(steps) > 0 {
+		fmt.Println("🔧 执行生成后步骤...")
+		results := installer.RunSteps(meta.Name, steps)
+		if failed := installer.Failed(results); len(failed) > 0 {
+			fmt.Printf("  ⚠️  以下步骤失败，可稍后手动执行：%s\n", strings.Join(failed, "、"))
+		}
+		fmt.Println()
+	}
+
 	// 完成提示
 	fmt.Println("✅ 项目初始化完成！")
 	fmt.Println()
 	fmt.Println("下一步操作：")
 	fmt.Printf("  1. cd %s\n", meta.Name)
 	fmt.Println("  2. 启动后端：make backend-dev")
-	fmt.Println("  3. 启动前端：make frontend-dev")
-	fmt.Println("  4. 访问：http://localhost:5173")
+	if meta.HasFeature("frontend") {
+		fmt.Println("  3. 启动前端：make frontend-dev")
+		fmt.Println("  4. 访问：http://localhost:5173")
+	} else {
+		fmt.Println("  3. 访问：http://localhost:8080/api/v1/health")
+	}
 	fmt.Println()
 
-	return nil
+	return printJSON(stdout, meta)
+}
+
+// postGenerateSteps 生成项目后依次执行的步骤
+func postGenerateSteps(meta *renderer.ProjectMeta) []installer.Step {
+	var steps []installer.Step
+	if !skipSwag {
+		steps = append(steps, installer.Step{
+			Name:     "生成 Swagger 文档",
+			Dir:      "backend",
+			Commands: [][]string{{"swag", "init", "-g", "cmd/server/main.go"}},
+		})
+	}
+
+	if !skipHooks {
+		for _, h := range meta.Hooks {
+			steps = append(steps, installer.ShellStep(h.Name, h.Dir, h.Run))
+		}
+	}
+
+	if !skipGit {
+		step := installer.Step{
+			Name: "初始化 Git 仓库",
+			Commands: [][]string{
+				{"git", "init", "-q"},
+				{"git", "add", "-A"},
+				{"git", "commit", "-q", "-m", "Initial commit"},
+			},
+		}
+		// 合并到已有仓库（或生成在其他仓库的子目录中）时不再创建仓库
+		if err := exec.Command("git", "-C", meta.Name, "rev-parse", "--is-inside-work-tree").Run(); err == nil {
+			step.Skip = "已在 Git 仓库中"
+		}
+		steps = append(steps, step)
+	}
+
+	if verify {
+		steps = append(steps, installer.Step{
+			Name:     "构建后端",
+			Dir:      "backend",
+			Commands: [][]string{{"go", "build", "./..."}},
+		})
+		if meta.HasFeature("frontend") {
+			step := installer.Step{
+				Name:     "构建前端",
+				Dir:      "frontend",
+				Commands: [][]string{{"npm", "run", "build"}},
+			}
+			if _, err := os.Stat(filepath.Join(meta.Name, "frontend", "node_modules")); err != nil {
+				step.Skip = "未安装前端依赖"
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// printJSON 指定 --json 时输出项目信息
+func printJSON(w io.Writer, meta *renderer.ProjectMeta) error {
+	if !jsonOutput {
+		return nil
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(meta)
 }
 
-func collectProjectInfo() (*renderer.ProjectMeta, error) {
+func collectProjectInfo(cmd *cobra.Command) (*renderer.ProjectMeta, error) {
 	meta := &renderer.ProjectMeta{
-		Name:        projectName,
-		Description: projectDesc,
-		Version:     projectVersion,
-		Module:      modulePath,
-		TemplateURL: templateURL,
+		Name:           projectName,
+		Description:    projectDesc,
+		Module:         modulePath,
+		TemplateURL:    templateURL,
+		TemplateDir:    templateDir,
+		TemplateZip:    templateZip,
+		Offline:        offline,
+		TemplateRef:    templateRef,
+		TemplateSHA256: templateSHA256,
+		NoCache:        noCache,
+		Download: renderer.DownloadOptions{
+			Token:   templateToken,
+			Proxy:   proxyURL,
+			CACert:  caCert,
+			Timeout: timeout,
+			Retries: retries,
+		},
+		NonInteractive: nonInteractive,
+		DryRun:         dryRun,
+		Merge:          merge,
+		Force:          force,
+	}
+	if cmd.Flags().Changed("version") {
+		meta.Version = projectVersion
+	}
+	if cmd.Flags().Changed("features") {
+		meta.Features = make([]string, 0, len(features))
+		for _, f := range features {
+			meta.Features = append(meta.Features, strings.TrimSpace(f))
+		}
+	}
+
+	// 答案文件补充命令行未指定的信息
+	if answersFile != "" {
+		answers, err := renderer.LoadAnswers(answersFile)
+		if err != nil {
+			return nil, err
+		}
+		answers.Apply(meta)
 	}
 
 	// 使用交互式输入收集信息
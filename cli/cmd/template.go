@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/richer/ai_skeleton/cli/internal/renderer"
+	"github.com/spf13/cobra"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "模板缓存管理",
+	Long: `管理 ais init 下载的模板缓存。
+
+缓存目录默认为 ~/.cache/ais/templates，可通过环境变量 AIS_CACHE_DIR 覆盖。
+私有仓库的模板通过环境变量 AIS_TEMPLATE_TOKEN 或 ~/.netrc 认证。`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "列出缓存的模板",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := renderer.DefaultTemplateCache()
+		if err != nil {
+			return err
+		}
+		entries, err := cache.List()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("没有缓存的模板")
+			return nil
+		}
+
+		fmt.Printf("缓存目录：%s\n\n", cache.Dir)
+		for _, e := range entries {
+			fmt.Printf("  %s\n", e.Label())
+			fmt.Printf("    SHA-256：%s\n", e.SHA256)
+			fmt.Printf("    大小：%.1f KB  获取时间：%s\n", float64(e.Size)/1024, e.FetchedAt.Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	},
+}
+
+var templateCleanCmd = &cobra.Command{
+	Use:   "clean [模板地址]",
+	Short: "清理模板缓存",
+	Long:  `删除缓存的模板，指定模板地址时只删除该地址的所有版本。`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := renderer.DefaultTemplateCache()
+		if err != nil {
+			return err
+		}
+		url := ""
+		if len(args) > 0 {
+			url = args[0]
+		}
+		removed, err := cache.Clean(url)
+		if err != nil {
+			return fmt.Errorf("清理缓存失败: %w", err)
+		}
+		fmt.Printf("✓ 已删除 %d 个缓存的模板\n", removed)
+		return nil
+	},
+}
+
+var templateUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "更新缓存的模板",
+	Long:  `通过 ETag 重新校验所有缓存的模板，下载有变化的模板（固定 commit 的模板不会变化，跳过）。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cache, err := renderer.DefaultTemplateCache()
+		if err != nil {
+			return err
+		}
+		changed, err := cache.Update()
+		for _, e := range changed {
+			fmt.Printf("  ✓ 已更新 %s\n", e.Label())
+		}
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			fmt.Println("✓ 所有缓存的模板均为最新")
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateCleanCmd)
+	templateCmd.AddCommand(templateUpdateCmd)
+}
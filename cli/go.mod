@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/manifoldco/promptui v0.9.0
 	github.com/spf13/cobra v1.8.0
+	go.yaml.in/yaml/v3 v3.0.4
 )
 
 require (
@@ -0,0 +1,512 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// MCPToolOptions mcp-tool 生成选项
+type MCPToolOptions struct {
+	BackendDir string   // 后端目录（包含 go.mod）
+	Methods    []string // 要生成的方法，为空表示全部
+	WithTest   bool     // 是否生成测试
+}
+
+// MCPToolResult 生成结果
+type MCPToolResult struct {
+	Tools   []string // 生成的工具名
+	Files   []string // 写入的文件
+	Skipped []string // 跳过的方法及原因
+}
+
+// toolSpec 单个工具的生成参数
+type toolSpec struct {
+	FuncName    string
+	TestName    string
+	ToolName    string
+	Ref         string // 如 health.Check
+	Description string
+	Schema      string
+	Bind        string // 绑定参数的语句
+	Call        string // 调用服务并返回的语句
+	TestArgs    string
+	Constructor string
+	WithTest    bool
+}
+
+// GenerateMCPTool 为服务方法生成 registerXTool 函数与测试，并在 RegisterAllTools 中注册
+func GenerateMCPTool(svc *ServiceInfo, opts MCPToolOptions) (*MCPToolResult, error) {
+	mcpDir := filepath.Join(opts.BackendDir, "internal", "mcp")
+	existing, err := scanMCPPackage(mcpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	methods, err := selectMethods(svc, opts.Methods)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MCPToolResult{}
+	imports := map[string]bool{"context": true, svc.ImportPath: true}
+	var specs []toolSpec
+	for _, m := range methods {
+		spec, err := svc.buildToolSpec(m, imports)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", m.Name, err))
+			continue
+		}
+		if existing.funcs[spec.FuncName] || existing.tools[spec.ToolName] {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: 工具 %s 已存在", m.Name, spec.ToolName))
+			continue
+		}
+		spec.WithTest = opts.WithTest && !existing.funcs[spec.TestName]
+		specs = append(specs, spec)
+		result.Tools = append(result.Tools, spec.ToolName)
+	}
+	if len(specs) == 0 {
+		return result, nil
+	}
+
+	module, err := readModulePath(filepath.Join(opts.BackendDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	// 工具文件
+	toolsFile := filepath.Join(mcpDir, "tools_"+svc.Package+".go")
+	if err := appendGenerated(toolsFile, "package mcp\n", toolFuncTemplate, specs, keys(imports)); err != nil {
+		return nil, err
+	}
+	result.Files = append(result.Files, toolsFile)
+
+	// 测试文件
+	var testSpecs []toolSpec
+	for _, spec := range specs {
+		if spec.WithTest {
+			testSpecs = append(testSpecs, spec)
+		}
+	}
+	if len(testSpecs) > 0 {
+		testFile := filepath.Join(mcpDir, "tools_"+svc.Package+"_test.go")
+		testImports := []string{"testing", module + "/internal/mcp", module + "/internal/testutil", module + "/internal/testutil/mcptest"}
+		if err := appendGenerated(testFile, "package mcp_test\n", toolTestTemplate, testSpecs, testImports); err != nil {
+			return nil, err
+		}
+		result.Files = append(result.Files, testFile)
+	}
+
+	// 在 RegisterAllTools 中注册
+	registry := filepath.Join(mcpDir, "tools.go")
+	if err := insertRegistrations(registry, specs); err != nil {
+		return nil, err
+	}
+	result.Files = append(result.Files, registry)
+
+	return result, nil
+}
+
+// buildToolSpec 根据方法签名生成参数绑定与调用代码
+func (s *ServiceInfo) buildToolSpec(m Method, imports map[string]bool) (toolSpec, error) {
+	if m.Unsupported != "" {
+		return toolSpec{}, fmt.Errorf("%s", m.Unsupported)
+	}
+
+	base := exportName(s.Package) + m.Name
+	spec := toolSpec{
+		FuncName:    "register" + base + "Tool",
+		TestName:    "Test" + base + "Tool",
+		ToolName:    snakeCase(s.Package) + "_" + snakeCase(m.Name),
+		Ref:         s.Package + "." + m.Name,
+		Description: m.Doc,
+		Constructor: s.Package + "." + s.Constructor,
+	}
+	if spec.Description == "" {
+		spec.Description = spec.Ref
+	}
+
+	var args []string
+	if m.HasContext {
+		args = append(args, "ctx")
+	}
+
+	var sc *schema
+	switch {
+	case len(m.Params) == 0:
+		sc = &schema{Type: "object", Properties: []property{}, Required: []string{}}
+	case len(m.Params) == 1 && s.isStruct(m.Params[0].Type):
+		// 单个结构体参数：直接绑定到该结构体
+		p := m.Params[0]
+		typ, err := s.typeString(p.Type, imports)
+		if err != nil {
+			return toolSpec{}, err
+		}
+		typ = strings.TrimPrefix(typ, "*")
+		sc = s.typeSchema(p.Type, map[string]bool{})
+		spec.Bind = fmt.Sprintf("var req %s\nif err := BindParams(params, &req); err != nil {\nreturn nil, err\n}\n", typ)
+		if _, isPtr := p.Type.(*ast.StarExpr); isPtr {
+			args = append(args, "&req")
+		} else {
+			args = append(args, "req")
+		}
+	default:
+		// 多个参数：组合为匿名结构体
+		var fields []string
+		for _, p := range m.Params {
+			typ, err := s.typeString(p.Type, imports)
+			if err != nil {
+				return toolSpec{}, err
+			}
+			field := fieldName(p.Name)
+			fields = append(fields, fmt.Sprintf("%s %s `json:%q`", field, typ, p.Name))
+			if _, variadic := p.Type.(*ast.Ellipsis); variadic {
+				field += "..."
+			}
+			args = append(args, "args."+field)
+		}
+		sc = s.paramsSchema(m.Params)
+		spec.Bind = fmt.Sprintf("var args struct {\n%s\n}\nif err := BindParams(params, &args); err != nil {\nreturn nil, err\n}\n", strings.Join(fields, "\n"))
+	}
+	if sc.Properties == nil {
+		sc.Properties = []property{}
+	}
+	if sc.Required == nil {
+		sc.Required = []string{}
+	}
+	spec.Schema = sc.render()
+	spec.TestArgs = sc.zeroValue()
+
+	call := fmt.Sprintf("svc.%s(%s)", m.Name, strings.Join(args, ", "))
+	switch {
+	case m.Result != nil && m.HasError:
+		spec.Call = "return " + call
+	case m.HasError:
+		spec.Call = "return nil, " + call
+	case m.Result != nil:
+		spec.Call = "return " + call + ", nil"
+	default:
+		spec.Call = call + "\nreturn nil, nil"
+	}
+	return spec, nil
+}
+
+// isStruct 参数是否为服务包内的结构体（或其指针）
+func (s *ServiceInfo) isStruct(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	_, isStruct := s.types[ident.Name].(*ast.StructType)
+	return isStruct
+}
+
+// selectMethods 按名称选择方法
+func selectMethods(svc *ServiceInfo, names []string) ([]Method, error) {
+	if len(names) == 0 {
+		return svc.Methods, nil
+	}
+	methods := make([]Method, 0, len(names))
+	for _, name := range names {
+		m, ok := svc.Method(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("接口 %s 中没有方法 %s", svc.Interface, name)
+		}
+		methods = append(methods, m)
+	}
+	return methods, nil
+}
+
+var toolFuncTemplate = template.Must(template.New("tool").Parse(`{{range .}}
+// {{.FuncName}} 注册 {{.Ref}} 工具
+func {{.FuncName}}(adapter MCPAdapter) error {
+	schema := ToolSchema{
+		Name:        {{printf "%q" .ToolName}},
+		Description: {{printf "%q" .Description}},
+		Parameters: {{.Schema}},
+	}
+
+	handler := func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		{{- if .Bind}}
+		{{.Bind}}
+		{{- end}}
+		// 每次调用时创建 service
+		svc := {{.Constructor}}()
+		{{.Call}}
+	}
+
+	return adapter.RegisterTool({{printf "%q" .ToolName}}, schema, handler)
+}
+{{end}}`))
+
+var toolTestTemplate = template.Must(template.New("test").Parse(`{{range .}}
+func {{.TestName}}(t *testing.T) {
+	adapter := mcp.NewMCPAdapter()
+	testutil.AssertNoError(t, mcp.RegisterAllTools(adapter))
+	c := mcptest.NewClient(t, adapter)
+
+	// TODO: 按业务补充参数与断言
+	result := c.CallTool(t, {{printf "%q" .ToolName}}, {{.TestArgs}})
+	testutil.AssertEqual(t, result.IsError, false)
+}
+{{end}}`))
+
+// appendGenerated 渲染模板并追加到文件（不存在时新建），补齐缺失的导入后格式化
+func appendGenerated(file, header string, tmpl *template.Template, specs []toolSpec, imports []string) error {
+	src, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		src = []byte(header)
+	} else if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(src)
+	if err := tmpl.Execute(&buf, specs); err != nil {
+		return fmt.Errorf("渲染模板失败: %w", err)
+	}
+
+	out, err := ensureImports(buf.Bytes(), imports)
+	if err != nil {
+		return fmt.Errorf("生成 %s 失败: %w", file, err)
+	}
+	return os.WriteFile(file, out, 0644)
+}
+
+// ensureImports 补齐缺失的导入并格式化源码
+func ensureImports(src []byte, paths []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	have := make(map[string]bool)
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		have[path] = true
+	}
+	var missing []string
+	for _, p := range paths {
+		if !have[p] {
+			missing = append(missing, strconv.Quote(p))
+		}
+	}
+	if len(missing) == 0 {
+		return format.Source(src)
+	}
+
+	// 有括号的 import 块时插入到块内，否则在 package 声明后新增（标准库与其他包分组）
+	offset := fset.Position(file.Name.End()).Offset
+	text := "\n\nimport (\n" + groupImports(missing) + "\n)"
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if ok && gen.Tok == token.IMPORT && gen.Rparen.IsValid() {
+			offset = fset.Position(gen.Rparen).Offset
+			text = strings.Join(missing, "\n") + "\n"
+			break
+		}
+	}
+
+	out := make([]byte, 0, len(src)+len(text))
+	out = append(out, src[:offset]...)
+	out = append(out, text...)
+	out = append(out, src[offset:]...)
+	return format.Source(out)
+}
+
+// groupImports 标准库在前、其他包在后，两组之间空一行
+func groupImports(quoted []string) string {
+	var std, other []string
+	for _, q := range quoted {
+		path, _ := strconv.Unquote(q)
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, q)
+		} else {
+			std = append(std, q)
+		}
+	}
+	if len(std) == 0 || len(other) == 0 {
+		return strings.Join(append(std, other...), "\n")
+	}
+	return strings.Join(std, "\n") + "\n\n" + strings.Join(other, "\n")
+}
+
+// insertRegistrations 在 RegisterAllTools 最后一个 registerXTool 调用之后插入新的注册调用
+func insertRegistrations(file string, specs []toolSpec) error {
+	src, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	var fn *ast.FuncDecl
+	for _, decl := range f.Decls {
+		if d, ok := decl.(*ast.FuncDecl); ok && d.Recv == nil && d.Name.Name == "RegisterAllTools" {
+			fn = d
+		}
+	}
+	if fn == nil || fn.Body == nil {
+		return fmt.Errorf("%s 中未找到 RegisterAllTools", file)
+	}
+
+	offset := fset.Position(fn.Body.Lbrace).Offset + 1
+	for _, stmt := range fn.Body.List {
+		if registerCallName(stmt) != "" {
+			offset = fset.Position(stmt.End()).Offset
+		}
+	}
+
+	var b strings.Builder
+	for _, spec := range specs {
+		fmt.Fprintf(&b, "\n\n// 注册 %s 工具\nif err := %s(adapter); err != nil {\nlog.Printf(\"Failed to register %s tool: %%v\", err)\nreturn err\n}", spec.Ref, spec.FuncName, spec.ToolName)
+	}
+
+	out := make([]byte, 0, len(src)+b.Len())
+	out = append(out, src[:offset]...)
+	out = append(out, b.String()...)
+	out = append(out, src[offset:]...)
+
+	formatted, err := format.Source(out)
+	if err != nil {
+		return fmt.Errorf("更新 %s 失败: %w", file, err)
+	}
+	return os.WriteFile(file, formatted, 0644)
+}
+
+// registerCallName 语句形如 if err := registerXTool(adapter); err != nil {...} 时返回函数名
+func registerCallName(stmt ast.Stmt) string {
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	if !ok {
+		return ""
+	}
+	assign, ok := ifStmt.Init.(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 {
+		return ""
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	ident, ok := call.Fun.(*ast.Ident)
+	if !ok || !strings.HasPrefix(ident.Name, "register") || !strings.HasSuffix(ident.Name, "Tool") {
+		return ""
+	}
+	return ident.Name
+}
+
+// mcpPackage mcp 包中已有的函数与工具名
+type mcpPackage struct {
+	funcs map[string]bool
+	tools map[string]bool
+}
+
+// scanMCPPackage 收集 mcp 包（含测试）中已定义的函数名与 RegisterTool 注册的工具名
+func scanMCPPackage(dir string) (*mcpPackage, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析 mcp 包失败: %w", err)
+	}
+
+	existing := &mcpPackage{funcs: make(map[string]bool), tools: make(map[string]bool)}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.FuncDecl:
+					if node.Recv == nil {
+						existing.funcs[node.Name.Name] = true
+					}
+				case *ast.CallExpr:
+					sel, ok := node.Fun.(*ast.SelectorExpr)
+					if !ok || sel.Sel.Name != "RegisterTool" || len(node.Args) == 0 {
+						return true
+					}
+					if lit, ok := node.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						name, _ := strconv.Unquote(lit.Value)
+						existing.tools[name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+	return existing, nil
+}
+
+// exportName 首字母大写
+func exportName(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// initialisms 按 Go 命名习惯整体大写的缩写
+var initialisms = map[string]bool{"id": true, "ids": true, "url": true, "uri": true, "ip": true, "api": true, "http": true, "json": true, "uuid": true}
+
+// fieldName 参数名转为导出字段名，如 id -> ID、userId -> UserID
+func fieldName(name string) string {
+	words := strings.Split(snakeCase(name), "_")
+	for i, w := range words {
+		if initialisms[w] {
+			words[i] = strings.ToUpper(w)
+			if w == "ids" {
+				words[i] = "IDs"
+			}
+			continue
+		}
+		words[i] = exportName(w)
+	}
+	return strings.Join(words, "")
+}
+
+// snakeCase 驼峰转下划线，如 GetUserByID -> get_user_by_id
+func snakeCase(s string) string {
+	r := []rune(s)
+	var b strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) {
+			prevLower := i > 0 && (unicode.IsLower(r[i-1]) || unicode.IsDigit(r[i-1]))
+			nextLower := i > 0 && i+1 < len(r) && unicode.IsLower(r[i+1]) && unicode.IsUpper(r[i-1])
+			if prevLower || nextLower {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(c))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// keys 排序后的 map 键
+func keys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
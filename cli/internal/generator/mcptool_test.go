@@ -0,0 +1,200 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRegistry = `package mcp
+
+import "log"
+
+// RegisterAllTools 统一注册所有 MCP 工具
+func RegisterAllTools(adapter MCPAdapter) error {
+	// 注册健康检查工具
+	if err := registerHealthTool(adapter); err != nil {
+		log.Printf("Failed to register health tool: %v", err)
+		return err
+	}
+
+	log.Printf("Successfully registered %d MCP tools", len(adapter.ListTools()))
+	return nil
+}
+
+func registerHealthTool(adapter MCPAdapter) error {
+	return adapter.RegisterTool("health_check", ToolSchema{}, nil)
+}
+`
+
+const testService = `package order
+
+import "context"
+
+// Order 订单
+type Order struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Note string ` + "`json:\"note,omitempty\"`" + ` // 备注
+}
+
+// OrderService 订单服务
+type OrderService interface {
+	// Create 创建订单
+	Create(ctx context.Context, order *Order) (*Order, error)
+	// Cancel 取消订单
+	Cancel(ctx context.Context, orderId int64, reason string) error
+	// Split 不支持
+	Split() (int, int)
+}
+
+// NewOrderService 创建订单服务
+func NewOrderService() OrderService { return nil }
+`
+
+// writeFile 写入测试文件
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGenerateMCPTool(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.23\n")
+	writeFile(t, filepath.Join(dir, "internal", "mcp", "tools.go"), testRegistry)
+	writeFile(t, filepath.Join(dir, "internal", "service", "order", "order.go"), testService)
+
+	svc, err := ParseService(dir, "order.OrderService")
+	if err != nil {
+		t.Fatalf("ParseService: %v", err)
+	}
+	if svc.Constructor != "NewOrderService" || len(svc.Methods) != 3 {
+		t.Fatalf("unexpected service: %+v", svc)
+	}
+
+	result, err := GenerateMCPTool(svc, MCPToolOptions{BackendDir: dir, WithTest: true})
+	if err != nil {
+		t.Fatalf("GenerateMCPTool: %v", err)
+	}
+	if strings.Join(result.Tools, ",") != "order_create,order_cancel" || len(result.Skipped) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	tools, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "tools_order.go"))
+	for _, want := range []string{
+		`var req order.Order`,
+		`return svc.Create(ctx, &req)`,
+		`OrderID int64  ` + "`json:\"orderId\"`",
+		`return nil, svc.Cancel(ctx, args.OrderID, args.Reason)`,
+		`"description": "备注"`,
+		`"required": []string{"id"}`,
+	} {
+		if !strings.Contains(string(tools), want) {
+			t.Errorf("tools_order.go missing %q\n%s", want, tools)
+		}
+	}
+
+	registry, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "tools.go"))
+	health := strings.Index(string(registry), "registerHealthTool(adapter)")
+	create := strings.Index(string(registry), "registerOrderCreateTool(adapter)")
+	cancel := strings.Index(string(registry), "registerOrderCancelTool(adapter)")
+	if !(health < create && create < cancel) {
+		t.Errorf("registrations not inserted in order:\n%s", registry)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "internal", "mcp", "tools_order_test.go")); err != nil {
+		t.Errorf("test file not generated: %v", err)
+	}
+
+	// 再次生成时跳过已存在的工具
+	result, err = GenerateMCPTool(svc, MCPToolOptions{BackendDir: dir, Methods: []string{"Create"}})
+	if err != nil || len(result.Tools) != 0 || len(result.Skipped) != 1 {
+		t.Fatalf("expected existing tool to be skipped: %+v, %v", result, err)
+	}
+}
+
+func TestGenerateMCPTool_TimeField(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n\ngo 1.23\n")
+	writeFile(t, filepath.Join(dir, "internal", "mcp", "tools.go"), testRegistry)
+	writeFile(t, filepath.Join(dir, "internal", "service", "order", "order.go"), `package order
+
+import (
+	"context"
+	"time"
+)
+
+// GetRequest 查询参数
+type GetRequest struct {
+	ID    int64     `+"`json:\"id\"`"+`
+	Since time.Time `+"`json:\"since\"`"+`
+	Raw   []byte    `+"`json:\"raw\"`"+`
+}
+
+// OrderService 订单服务
+type OrderService interface {
+	// Get 查询订单
+	Get(ctx context.Context, req *GetRequest) (int, error)
+}
+
+// NewOrderService 创建订单服务
+func NewOrderService() OrderService { return nil }
+`)
+
+	svc, err := ParseService(dir, "order.OrderService")
+	if err != nil {
+		t.Fatalf("ParseService: %v", err)
+	}
+	if _, err := GenerateMCPTool(svc, MCPToolOptions{BackendDir: dir, WithTest: true}); err != nil {
+		t.Fatalf("GenerateMCPTool: %v", err)
+	}
+
+	test, _ := os.ReadFile(filepath.Join(dir, "internal", "mcp", "tools_order_test.go"))
+	if !strings.Contains(string(test), `"since": "2006-01-02T15:04:05Z"`) {
+		t.Errorf("time.Time 参数应使用 RFC 3339 示例值:\n%s", test)
+	}
+	if !strings.Contains(string(test), `"raw": ""`) {
+		t.Errorf("[]byte 参数应使用合法的 base64 示例值:\n%s", test)
+	}
+}
+
+func TestSchemaZeroValue(t *testing.T) {
+	tests := []struct {
+		sc   *schema
+		want string
+	}{
+		{sc: &schema{Type: "string"}, want: `""`},
+		{sc: &schema{Type: "string", Format: "date-time"}, want: `"2006-01-02T15:04:05Z"`},
+		{sc: &schema{Type: "integer"}, want: "0"},
+		{sc: &schema{Type: "object", Properties: []property{{Name: "at", Schema: &schema{Type: "string", Format: "date-time"}}}, Required: []string{"at"}}, want: `map[string]interface{}{"at": "2006-01-02T15:04:05Z"}`},
+	}
+	for _, tt := range tests {
+		if got := tt.sc.zeroValue(); got != tt.want {
+			t.Errorf("zeroValue(%+v) = %s, want %s", tt.sc, got, tt.want)
+		}
+	}
+}
+
+func TestSnakeCaseAndFieldName(t *testing.T) {
+	tests := []struct {
+		in, snake, field string
+	}{
+		{in: "GetUserByID", snake: "get_user_by_id", field: "GetUserByID"},
+		{in: "orderId", snake: "order_id", field: "OrderID"},
+		{in: "HTTPStatus", snake: "http_status", field: "HTTPStatus"},
+		{in: "name", snake: "name", field: "Name"},
+	}
+	for _, tt := range tests {
+		if got := snakeCase(tt.in); got != tt.snake {
+			t.Errorf("snakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := fieldName(tt.in); got != tt.field {
+			t.Errorf("fieldName(%q) = %q, want %q", tt.in, got, tt.field)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// schema 由 Go 类型推导出的 JSON Schema（保持字段顺序，便于生成稳定的代码）
+type schema struct {
+	Type        string
+	Format      string
+	Description string
+	Items       *schema
+	Properties  []property
+	Required    []string
+}
+
+// property 对象属性
+type property struct {
+	Name   string
+	Schema *schema
+}
+
+// predeclared 内置类型
+var predeclared = map[string]string{
+	"string": "string", "bool": "boolean",
+	"int": "integer", "int8": "integer", "int16": "integer", "int32": "integer", "int64": "integer",
+	"uint": "integer", "uint8": "integer", "uint16": "integer", "uint32": "integer", "uint64": "integer",
+	"byte": "integer", "rune": "integer",
+	"float32": "number", "float64": "number",
+	"any": "", "error": "string",
+}
+
+// typeSchema 推导类型的 schema，seen 防止递归类型无限展开
+func (s *ServiceInfo) typeSchema(expr ast.Expr, seen map[string]bool) *schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if jsonType, ok := predeclared[t.Name]; ok {
+			return &schema{Type: jsonType}
+		}
+		underlying, ok := s.types[t.Name]
+		if !ok || seen[t.Name] {
+			return &schema{Type: "object"}
+		}
+		seen[t.Name] = true
+		defer delete(seen, t.Name)
+		return s.typeSchema(underlying, seen)
+	case *ast.StarExpr:
+		return s.typeSchema(t.X, seen)
+	case *ast.ArrayType:
+		if ident, ok := t.Elt.(*ast.Ident); ok && ident.Name == "byte" {
+			return &schema{Type: "string", Format: "byte"}
+		}
+		return &schema{Type: "array", Items: s.typeSchema(t.Elt, seen)}
+	case *ast.Ellipsis:
+		return &schema{Type: "array", Items: s.typeSchema(t.Elt, seen)}
+	case *ast.MapType:
+		return &schema{Type: "object"}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" {
+			switch t.Sel.Name {
+			case "Time":
+				return &schema{Type: "string", Format: "date-time"}
+			case "Duration":
+				return &schema{Type: "integer"}
+			}
+		}
+		return &schema{Type: "object"}
+	case *ast.StructType:
+		return s.structSchema(t, seen)
+	case *ast.InterfaceType:
+		return &schema{}
+	}
+	return &schema{Type: "object"}
+}
+
+// structSchema 按 json tag 推导结构体字段；非指针且未标记 omitempty 的字段为必填
+func (s *ServiceInfo) structSchema(st *ast.StructType, seen map[string]bool) *schema {
+	out := &schema{Type: "object"}
+	for _, field := range st.Fields.List {
+		// 内嵌结构体展开到当前层级
+		if len(field.Names) == 0 {
+			embedded := s.typeSchema(field.Type, seen)
+			out.Properties = append(out.Properties, embedded.Properties...)
+			out.Required = append(out.Required, embedded.Required...)
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			jsonName, omitempty, skip := jsonTag(field.Tag, name.Name)
+			if skip {
+				continue
+			}
+
+			prop := s.typeSchema(field.Type, seen)
+			prop.Description = fieldDoc(field)
+			out.Properties = append(out.Properties, property{Name: jsonName, Schema: prop})
+
+			if _, isPtr := field.Type.(*ast.StarExpr); !omitempty && !isPtr {
+				out.Required = append(out.Required, jsonName)
+			}
+		}
+	}
+	return out
+}
+
+// paramsSchema 多个基础参数组合为一个对象，参数均为必填
+func (s *ServiceInfo) paramsSchema(params []Param) *schema {
+	out := &schema{Type: "object"}
+	for _, p := range params {
+		out.Properties = append(out.Properties, property{Name: p.Name, Schema: s.typeSchema(p.Type, map[string]bool{})})
+		out.Required = append(out.Required, p.Name)
+	}
+	return out
+}
+
+// render 生成 schema 的 Go 字面量
+func (sc *schema) render() string {
+	var b strings.Builder
+	b.WriteString("map[string]interface{}{\n")
+	if sc.Type != "" {
+		fmt.Fprintf(&b, "%q: %q,\n", "type", sc.Type)
+	}
+	if sc.Format != "" {
+		fmt.Fprintf(&b, "%q: %q,\n", "format", sc.Format)
+	}
+	if sc.Description != "" {
+		fmt.Fprintf(&b, "%q: %q,\n", "description", sc.Description)
+	}
+	if sc.Items != nil {
+		fmt.Fprintf(&b, "%q: %s,\n", "items", sc.Items.render())
+	}
+	if sc.Type == "object" && (sc.Properties != nil || sc.Required != nil) {
+		b.WriteString(`"properties": map[string]interface{}{` + "\n")
+		for _, p := range sc.Properties {
+			fmt.Fprintf(&b, "%q: %s,\n", p.Name, p.Schema.render())
+		}
+		b.WriteString("},\n")
+
+		quoted := make([]string, 0, len(sc.Required))
+		for _, r := range sc.Required {
+			quoted = append(quoted, strconv.Quote(r))
+		}
+		fmt.Fprintf(&b, `"required": []string{%s},`+"\n", strings.Join(quoted, ", "))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// formatSamples 带 format 的字符串参数的示例值，需能反序列化为对应的 Go 类型
+var formatSamples = map[string]string{
+	"date-time": "2006-01-02T15:04:05Z", // time.Time
+	"byte":      "",                     // []byte（base64）
+}
+
+// zeroValue 必填参数的示例值（用于生成的测试）
+func (sc *schema) zeroValue() string {
+	switch sc.Type {
+	case "string":
+		return strconv.Quote(formatSamples[sc.Format])
+	case "integer", "number":
+		return "0"
+	case "boolean":
+		return "false"
+	case "array":
+		return "[]interface{}{}"
+	case "object":
+		var b strings.Builder
+		b.WriteString("map[string]interface{}{")
+		for _, p := range sc.Properties {
+			if contains(sc.Required, p.Name) {
+				fmt.Fprintf(&b, "%q: %s, ", p.Name, p.Schema.zeroValue())
+			}
+		}
+		return strings.TrimSuffix(b.String(), ", ") + "}"
+	}
+	return "nil"
+}
+
+// typeString 生成类型的 Go 源码，服务包内的类型加上包名前缀，并记录需要导入的包
+func (s *ServiceInfo) typeString(expr ast.Expr, imports map[string]bool) (string, error) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if _, ok := predeclared[t.Name]; ok {
+			return t.Name, nil
+		}
+		if _, ok := s.types[t.Name]; !ok || !ast.IsExported(t.Name) {
+			return "", fmt.Errorf("类型 %s 未导出或不在服务包中", t.Name)
+		}
+		imports[s.ImportPath] = true
+		return s.Package + "." + t.Name, nil
+	case *ast.StarExpr:
+		x, err := s.typeString(t.X, imports)
+		return "*" + x, err
+	case *ast.ArrayType:
+		elt, err := s.typeString(t.Elt, imports)
+		if t.Len != nil {
+			return "", fmt.Errorf("暂不支持数组类型")
+		}
+		return "[]" + elt, err
+	case *ast.Ellipsis:
+		elt, err := s.typeString(t.Elt, imports)
+		return "[]" + elt, err
+	case *ast.MapType:
+		key, err := s.typeString(t.Key, imports)
+		if err != nil {
+			return "", err
+		}
+		value, err := s.typeString(t.Value, imports)
+		return "map[" + key + "]" + value, err
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		if !ok {
+			return "", fmt.Errorf("暂不支持的类型")
+		}
+		path, ok := s.imports[pkg.Name]
+		if !ok {
+			return "", fmt.Errorf("未找到包 %s 的导入路径", pkg.Name)
+		}
+		imports[path] = true
+		return pkg.Name + "." + t.Sel.Name, nil
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			return "interface{}", nil
+		}
+	}
+	return "", fmt.Errorf("暂不支持的参数类型")
+}
+
+// jsonTag 解析 json tag，返回字段名、是否 omitempty、是否忽略
+func jsonTag(tag *ast.BasicLit, fieldName string) (string, bool, bool) {
+	if tag == nil {
+		return fieldName, false, false
+	}
+	raw, _ := strconv.Unquote(tag.Value)
+	value, ok := reflect.StructTag(raw).Lookup("json")
+	if !ok {
+		return fieldName, false, false
+	}
+	if value == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(value, ",")
+	name := parts[0]
+	if name == "" {
+		name = fieldName
+	}
+	return name, contains(parts[1:], "omitempty"), false
+}
+
+// fieldDoc 字段注释作为描述
+func fieldDoc(field *ast.Field) string {
+	doc := strings.TrimSpace(field.Doc.Text())
+	if doc == "" {
+		doc = strings.TrimSpace(field.Comment.Text())
+	}
+	return strings.Join(strings.Fields(doc), " ")
+}
+
+// contains 切片是否包含元素
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,264 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ServiceInfo 解析出的服务接口
+type ServiceInfo struct {
+	Package     string              // 包名，如 health
+	Interface   string              // 接口名，如 HealthService
+	ImportPath  string              // 包导入路径
+	Constructor string              // 无参构造函数，如 NewHealthService
+	Methods     []Method            // 接口方法
+	imports     map[string]string   // 包内文件的导入：别名 -> 路径
+	types       map[string]ast.Expr // 包内类型定义：类型名 -> 底层类型
+}
+
+// Method 接口方法
+type Method struct {
+	Name        string
+	Doc         string
+	HasContext  bool     // 第一个参数是否为 context.Context
+	Params      []Param  // 除 context 外的参数
+	Result      ast.Expr // 非 error 返回值，可为 nil
+	HasError    bool     // 是否返回 error
+	Unsupported string   // 不支持生成的原因，为空表示支持
+}
+
+// Param 方法参数
+type Param struct {
+	Name string
+	Type ast.Expr
+}
+
+// ParseService 解析 backend/internal/service/<pkg> 下的服务接口，ref 形如 health.HealthService
+func ParseService(backendDir, ref string) (*ServiceInfo, error) {
+	pkgName, ifaceName, ok := strings.Cut(ref, ".")
+	if !ok || pkgName == "" || ifaceName == "" {
+		return nil, fmt.Errorf("服务接口格式错误，应为 包名.接口名（如 health.HealthService）: %s", ref)
+	}
+
+	module, err := readModulePath(filepath.Join(backendDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(backendDir, "internal", "service", pkgName)
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析服务包失败: %w", err)
+	}
+	pkg, ok := pkgs[pkgName]
+	if !ok {
+		return nil, fmt.Errorf("目录 %s 中未找到包 %s", dir, pkgName)
+	}
+
+	svc := &ServiceInfo{
+		Package:    pkgName,
+		Interface:  ifaceName,
+		ImportPath: module + "/internal/service/" + pkgName,
+		imports:    make(map[string]string),
+		types:      make(map[string]ast.Expr),
+	}
+
+	var iface *ast.InterfaceType
+	var ifaceDoc map[string]string
+	for _, file := range sortedFiles(pkg) {
+		for _, imp := range file.Imports {
+			path, _ := strconv.Unquote(imp.Path.Value)
+			alias := filepath.Base(path)
+			if imp.Name != nil {
+				alias = imp.Name.Name
+			}
+			svc.imports[alias] = path
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					ts, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					svc.types[ts.Name.Name] = ts.Type
+					if t, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == ifaceName {
+						iface = t
+						ifaceDoc = methodDocs(t)
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil && isConstructor(d, ifaceName) && svc.Constructor == "" {
+					svc.Constructor = d.Name.Name
+				}
+			}
+		}
+	}
+
+	if iface == nil {
+		return nil, fmt.Errorf("包 %s 中未找到接口 %s", pkgName, ifaceName)
+	}
+	if svc.Constructor == "" {
+		return nil, fmt.Errorf("未找到返回 %s 的无参构造函数（如 New%s()）", ifaceName, ifaceName)
+	}
+
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			continue
+		}
+		name := field.Names[0].Name
+		m := parseMethod(name, fn)
+		m.Doc = ifaceDoc[name]
+		svc.Methods = append(svc.Methods, m)
+	}
+	return svc, nil
+}
+
+// Method 按名称查找方法
+func (s *ServiceInfo) Method(name string) (Method, bool) {
+	for _, m := range s.Methods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return Method{}, false
+}
+
+// parseMethod 解析方法签名，仅支持 (T, error)、error、T 三种返回形式
+func parseMethod(name string, fn *ast.FuncType) Method {
+	m := Method{Name: name}
+
+	index := 0
+	for _, field := range fn.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent("")}
+		}
+		for _, n := range names {
+			if index == 0 && isContext(field.Type) {
+				m.HasContext = true
+				index++
+				continue
+			}
+			paramName := n.Name
+			if paramName == "" || paramName == "_" {
+				paramName = fmt.Sprintf("arg%d", index)
+			}
+			m.Params = append(m.Params, Param{Name: paramName, Type: field.Type})
+			index++
+		}
+	}
+
+	var results []ast.Expr
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				results = append(results, field.Type)
+			}
+		}
+	}
+
+	switch {
+	case len(results) == 0:
+	case len(results) == 1 && isError(results[0]):
+		m.HasError = true
+	case len(results) == 1:
+		m.Result = results[0]
+	case len(results) == 2 && isError(results[1]):
+		m.Result = results[0]
+		m.HasError = true
+	default:
+		m.Unsupported = "返回值应为 (T, error)、error 或 T"
+	}
+	return m
+}
+
+// methodDocs 收集接口方法的注释，去掉开头的方法名（如 "Check 执行健康检查" -> "执行健康检查"）
+func methodDocs(iface *ast.InterfaceType) map[string]string {
+	docs := make(map[string]string)
+	for _, field := range iface.Methods.List {
+		if len(field.Names) == 0 {
+			continue
+		}
+		name := field.Names[0].Name
+		doc := strings.TrimSpace(field.Doc.Text())
+		if doc == "" {
+			doc = strings.TrimSpace(field.Comment.Text())
+		}
+		doc = strings.TrimSpace(strings.TrimPrefix(doc, name))
+		docs[name] = strings.Join(strings.Fields(doc), " ")
+	}
+	return docs
+}
+
+// isConstructor 是否为返回该接口的无参构造函数
+func isConstructor(fn *ast.FuncDecl, iface string) bool {
+	if len(fn.Type.Params.List) != 0 || fn.Type.Results == nil || len(fn.Type.Results.List) != 1 {
+		return false
+	}
+	ident, ok := fn.Type.Results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == iface
+}
+
+// isContext 是否为 context.Context
+func isContext(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
+
+// isError 是否为 error
+func isError(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
+
+// sortedFiles 按文件名排序，保证生成结果稳定
+func sortedFiles(pkg *ast.Package) []*ast.File {
+	names := make([]string, 0, len(pkg.Files))
+	for name := range pkg.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		files = append(files, pkg.Files[name])
+	}
+	return files
+}
+
+// readModulePath 读取 go.mod 中的模块路径
+func readModulePath(goMod string) (string, error) {
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 失败: %w", goMod, err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "module ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "module ")), nil
+		}
+	}
+	return "", fmt.Errorf("%s 中未找到 module 声明", goMod)
+}
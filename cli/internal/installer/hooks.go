@@ -0,0 +1,114 @@
+package installer
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// outputTailLines 步骤失败时显示的输出行数
+const outputTailLines = 10
+
+// Step 生成项目后执行的一个步骤，包含一条或多条依次执行的命令
+type Step struct {
+	Name     string     // 显示名称
+	Dir      string     // 工作目录，相对项目根目录
+	Commands [][]string // 命令及参数，任一命令失败时停止该步骤
+	Skip     string     // 非空时跳过该步骤并显示原因
+}
+
+// StepResult 步骤的执行结果
+type StepResult struct {
+	Name    string
+	Skipped string // 跳过原因
+	Err     error
+}
+
+// ShellStep 通过 shell 执行命令的步骤（模板清单中的钩子）
+func ShellStep(name, dir, command string) Step {
+	if runtime.GOOS == "windows" {
+		return Step{Name: name, Dir: dir, Commands: [][]string{{"cmd", "/C", command}}}
+	}
+	return Step{Name: name, Dir: dir, Commands: [][]string{{"sh", "-c", command}}}
+}
+
+// RunSteps 依次执行步骤：缺少命令时跳过，失败时输出错误与最后几行输出并继续执行后续步骤
+func RunSteps(projectDir string, steps []Step) []StepResult {
+	results := make([]StepResult, 0, len(steps))
+	for _, step := range steps {
+		res := StepResult{Name: step.Name, Skipped: step.Skip}
+		if res.Skipped == "" {
+			res.Skipped = missingCommand(step)
+		}
+		if res.Skipped != "" {
+			fmt.Printf("  - %s：已跳过（%s）\n", step.Name, res.Skipped)
+			results = append(results, res)
+			continue
+		}
+
+		fmt.Printf("  ▶ %s...\n", step.Name)
+		output, err := runStep(filepath.Join(projectDir, filepath.FromSlash(step.Dir)), step.Commands)
+		if err != nil {
+			res.Err = err
+			fmt.Printf("  ⚠️  %s 失败: %v\n", step.Name, err)
+			for _, line := range tailLines(output, outputTailLines) {
+				fmt.Printf("      %s\n", line)
+			}
+		} else {
+			fmt.Printf("  ✓ %s\n", step.Name)
+		}
+		results = append(results, res)
+	}
+	return results
+}
+
+// Failed 失败步骤的名称
+func Failed(results []StepResult) []string {
+	var names []string
+	for _, r := range results {
+		if r.Err != nil {
+			names = append(names, r.Name)
+		}
+	}
+	return names
+}
+
+// missingCommand 步骤依赖的命令未安装时返回跳过原因
+func missingCommand(step Step) string {
+	for _, c := range step.Commands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			return "未找到 " + c[0]
+		}
+	}
+	return ""
+}
+
+// runStep 在 dir 下依次执行命令，返回合并的标准输出与标准错误
+func runStep(dir string, commands [][]string) (string, error) {
+	var output bytes.Buffer
+	for _, c := range commands {
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Dir = dir
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+		if err := cmd.Run(); err != nil {
+			return output.String(), fmt.Errorf("%s: %w", strings.Join(c, " "), err)
+		}
+	}
+	return output.String(), nil
+}
+
+// tailLines 输出的最后 n 行（忽略末尾空行）
+func tailLines(output string, n int) []string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return nil
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
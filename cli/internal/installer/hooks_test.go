@@ -0,0 +1,75 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestRunSteps(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("需要 sh")
+	}
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "backend"), 0755)
+
+	results := RunSteps(dir, []Step{
+		ShellStep("写入文件", "backend", "echo ok > out.txt"),
+		ShellStep("失败", "", "echo boom; exit 3"),
+		{Name: "已跳过", Skip: "原因", Commands: [][]string{{"sh", "-c", "exit 1"}}},
+		{Name: "缺少命令", Commands: [][]string{{"ais-missing-command"}}},
+		ShellStep("失败后继续", "", "touch after.txt"),
+	})
+
+	want := []struct {
+		skipped string
+		failed  bool
+	}{
+		{},
+		{failed: true},
+		{skipped: "原因"},
+		{skipped: "未找到 ais-missing-command"},
+		{},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("RunSteps() 返回 %d 项，want %d", len(results), len(want))
+	}
+	for i, w := range want {
+		if results[i].Skipped != w.skipped || (results[i].Err != nil) != w.failed {
+			t.Errorf("results[%d] = %+v, want %+v", i, results[i], w)
+		}
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(results[1].Err, &exitErr) {
+		t.Errorf("失败步骤应返回命令的退出错误: %v", results[1].Err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backend", "out.txt")); err != nil {
+		t.Error("步骤应在 dir 下执行")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "after.txt")); err != nil {
+		t.Error("失败后应继续执行后续步骤")
+	}
+	if got := Failed(results); len(got) != 1 || got[0] != "失败" {
+		t.Errorf("Failed() = %v", got)
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		output string
+		n      int
+		want   int
+	}{
+		{output: "", n: 10, want: 0},
+		{output: "a\nb\n", n: 10, want: 2},
+		{output: "1\n2\n3\n4\n", n: 2, want: 2},
+	}
+	for _, tt := range tests {
+		if got := tailLines(tt.output, tt.n); len(got) != tt.want {
+			t.Errorf("tailLines(%q, %d) = %v", tt.output, tt.n, got)
+		}
+	}
+}
@@ -7,25 +7,26 @@ import (
 	"strings"
 )
 
-// CheckEnvironment 检查环境依赖
-func CheckEnvironment() error {
+// CheckEnvironment 检查环境依赖，needNpm 为 false 时（不生成前端）不检查 npm
+func CheckEnvironment(needNpm bool) error {
 	// 检查 Go
 	goPath, err := exec.LookPath("go")
 	if err != nil {
 		return fmt.Errorf("❌ Go 未安装\n\n请访问 https://golang.org/dl/ 安装 Go 后重试")
 	}
+	goVersion := getCommandOutput("go", "version")
+	fmt.Printf("  ✓ Go: %s (路径: %s)\n", strings.TrimSpace(goVersion), goPath)
+
+	if !needNpm {
+		return nil
+	}
 
 	// 检查 npm
 	npmPath, err := exec.LookPath("npm")
 	if err != nil {
 		return fmt.Errorf("❌ npm 未安装\n\n请访问 https://nodejs.org/ 安装 Node.js 后重试")
 	}
-
-	// 获取版本信息
-	goVersion := getCommandOutput("go", "version")
 	npmVersion := getCommandOutput("npm", "-v")
-
-	fmt.Printf("  ✓ Go: %s (路径: %s)\n", strings.TrimSpace(goVersion), goPath)
 	fmt.Printf("  ✓ npm: v%s (路径: %s)\n", strings.TrimSpace(npmVersion), npmPath)
 
 	return nil
@@ -0,0 +1,59 @@
+package renderer
+
+import (
+	"fmt"
+	"os"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Answers 预先填写的项目信息（ais init --answers），用于 CI 等无法交互的场景
+type Answers struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Version     string            `yaml:"version"`
+	Module      string            `yaml:"module"`
+	Features    []string          `yaml:"features"` // 省略表示使用默认值或交互式选择，[] 表示不启用
+	Vars        map[string]string `yaml:"vars"`     // 模板清单中声明的变量
+}
+
+// LoadAnswers 读取答案文件
+func LoadAnswers(path string) (*Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取答案文件失败: %w", err)
+	}
+
+	var a Answers
+	if err := yaml.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("解析答案文件 %s 失败: %w", path, err)
+	}
+	return &a, nil
+}
+
+// Apply 填充 meta 中尚未指定的字段（命令行参数优先）
+func (a *Answers) Apply(meta *ProjectMeta) {
+	if meta.Name == "" {
+		meta.Name = a.Name
+	}
+	if meta.Description == "" {
+		meta.Description = a.Description
+	}
+	if meta.Version == "" {
+		meta.Version = a.Version
+	}
+	if meta.Module == "" {
+		meta.Module = a.Module
+	}
+	if meta.Features == nil && a.Features != nil {
+		meta.Features = append([]string{}, a.Features...)
+	}
+	for k, v := range a.Vars {
+		if meta.Vars == nil {
+			meta.Vars = make(map[string]string)
+		}
+		if _, ok := meta.Vars[k]; !ok {
+			meta.Vars[k] = v
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAnswers_Apply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	content := `name: shop
+description: 商城
+version: 2.0.0
+module: example.com/shop
+features: []
+vars:
+  Author: alice
+  Port: "9090"
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	answers, err := LoadAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 命令行参数优先于答案文件
+	meta := &ProjectMeta{Module: "example.com/flag", Vars: map[string]string{"Port": "8080"}}
+	answers.Apply(meta)
+
+	want := &ProjectMeta{
+		Name:        "shop",
+		Description: "商城",
+		Version:     "2.0.0",
+		Module:      "example.com/flag",
+		Features:    []string{},
+		Vars:        map[string]string{"Author": "alice", "Port": "8080"},
+	}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("Apply() = %+v, want %+v", meta, want)
+	}
+}
+
+func TestAnswers_FeaturesOmitted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	os.WriteFile(path, []byte("name: shop\n"), 0644)
+	answers, err := LoadAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &ProjectMeta{}
+	answers.Apply(meta)
+	if meta.Features != nil {
+		t.Errorf("未指定 features 时应保持 nil，got %v", meta.Features)
+	}
+}
+
+func TestLoadAnswers_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "answers.yaml")
+	os.WriteFile(path, []byte("name: [\n"), 0644)
+
+	if _, err := LoadAnswers(path); err == nil {
+		t.Error("格式错误时应返回错误")
+	}
+	if _, err := LoadAnswers(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("文件不存在时应返回错误")
+	}
+}
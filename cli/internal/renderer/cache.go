@@ -0,0 +1,383 @@
+package renderer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheDirEnv 覆盖模板缓存目录的环境变量
+const CacheDirEnv = "AIS_CACHE_DIR"
+
+// 缓存条目中的文件名
+const (
+	cacheArchiveFile = "template.zip"
+	cacheMetaFile    = "meta.json"
+)
+
+// githubArchive 匹配 GitHub 仓库归档地址，如 https://github.com/org/repo/archive/main.zip
+var githubArchive = regexp.MustCompile(`^(https://github\.com/[^/]+/[^/]+/archive/)(?:refs/(?:heads|tags)/)?[^/]+\.zip$`)
+
+// commitSHA 完整的 commit SHA，内容不可变，命中缓存后无需重新校验
+var commitSHA = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// ResolveTemplateURL 将模板地址与版本（tag、分支或 commit）组合为下载地址；
+// 地址中包含 {ref} 时直接替换，GitHub 归档地址替换归档名，其他地址不支持指定版本
+func ResolveTemplateURL(url, ref string) (string, error) {
+	if ref == "" {
+		return url, nil
+	}
+	if strings.Contains(url, "{ref}") {
+		return strings.ReplaceAll(url, "{ref}", ref), nil
+	}
+	if m := githubArchive.FindStringSubmatch(url); m != nil {
+		return m[1] + ref + ".zip", nil
+	}
+	return "", fmt.Errorf("无法为模板地址 %s 指定版本，请在地址中使用 {ref} 占位符", url)
+}
+
+// shorthandHosts 模板地址简写的默认主机
+var shorthandHosts = map[string]string{
+	"gh":    "github.com",
+	"gl":    "gitlab.com",
+	"gitea": "gitea.com",
+}
+
+// ExpandTemplateURL 展开模板地址简写 gh:org/repo、gl:group/repo、gitea:org/repo 为对应平台 API 的归档地址，
+// 可在仓库前指定自建实例的主机（如 gl:git.example.com/group/repo），以 @ref 指定版本；
+// 指定版本时返回的地址包含 {ref} 占位符。不是简写的地址原样返回
+func ExpandTemplateURL(raw, ref string) (string, string, error) {
+	scheme, rest, _ := strings.Cut(raw, ":")
+	defaultHost, ok := shorthandHosts[scheme]
+	if !ok {
+		return raw, ref, nil
+	}
+
+	repo, at, hasRef := strings.Cut(rest, "@")
+	if hasRef {
+		if at == "" {
+			return "", "", fmt.Errorf("模板地址 %s 中的版本为空", raw)
+		}
+		if ref != "" && ref != at {
+			return "", "", fmt.Errorf("模板地址 %s 已指定版本，与 --template-ref %s 冲突", raw, ref)
+		}
+		ref = at
+	}
+
+	host := defaultHost
+	parts := strings.Split(repo, "/")
+	if len(parts) > 2 && strings.Contains(parts[0], ".") {
+		host, parts = parts[0], parts[1:]
+	}
+	for _, p := range parts {
+		if p == "" {
+			parts = nil
+		}
+	}
+	if len(parts) < 2 || len(parts) > 2 && scheme != "gl" {
+		return "", "", fmt.Errorf("模板地址 %s 格式错误，应为 %s:org/repo[@ref]", raw, scheme)
+	}
+	repo = strings.Join(parts, "/")
+
+	var latest, archive string
+	switch scheme {
+	case "gh":
+		api := "https://api.github.com"
+		if host != defaultHost {
+			api = "https://" + host + "/api/v3" // GitHub Enterprise
+		}
+		latest = api + "/repos/" + repo + "/zipball"
+		archive = latest + "/{ref}"
+	case "gl":
+		latest = "https://" + host + "/api/v4/projects/" + url.PathEscape(repo) + "/repository/archive.zip"
+		archive = latest + "?sha={ref}"
+	case "gitea":
+		archive = "https://" + host + "/api/v1/repos/" + repo + "/archive/{ref}.zip"
+		latest = strings.ReplaceAll(archive, "{ref}", "main")
+	}
+	if ref == "" {
+		return latest, "", nil
+	}
+	return archive, ref, nil
+}
+
+// CacheEntry 缓存的模板归档
+type CacheEntry struct {
+	URL       string    `json:"url"`
+	Ref       string    `json:"ref,omitempty"`
+	ETag      string    `json:"etag,omitempty"`
+	SHA256    string    `json:"sha256"`
+	Size      int64     `json:"size"`
+	FetchedAt time.Time `json:"fetched_at"`
+	dir       string
+}
+
+// Label 条目的展示名称（地址@版本）
+func (e *CacheEntry) Label() string {
+	if e.Ref == "" {
+		return e.URL
+	}
+	return e.URL + "@" + e.Ref
+}
+
+// ArchivePath 缓存的 ZIP 路径
+func (e *CacheEntry) ArchivePath() string {
+	return filepath.Join(e.dir, cacheArchiveFile)
+}
+
+// TemplateCache 模板归档缓存，按 模板地址 + 版本 存放
+type TemplateCache struct {
+	Dir     string
+	Options DownloadOptions // 下载选项（认证、代理、重试等）
+}
+
+// DefaultTemplateCache 默认缓存目录：$AIS_CACHE_DIR 或 <用户缓存目录>/ais/templates（Linux 下为 ~/.cache/ais/templates）
+func DefaultTemplateCache() (*TemplateCache, error) {
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return &TemplateCache{Dir: dir}, nil
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("无法确定缓存目录: %w", err)
+	}
+	return &TemplateCache{Dir: filepath.Join(base, "ais", "templates")}, nil
+}
+
+// Fetch 获取模板归档：未缓存时下载；已缓存时通过 ETag 重新校验，未变化则直接使用缓存；
+// 网络不可用时退回到缓存。checksum 非空时校验磁盘上归档的 SHA-256
+func (c *TemplateCache) Fetch(url, ref, checksum string) (*CacheEntry, error) {
+	entry, cached := c.lookup(url, ref)
+
+	if cached && commitSHA.MatchString(ref) {
+		fmt.Println("  ✓ 使用缓存的模板（固定 commit）")
+		return entry, verifyFileChecksum(entry.ArchivePath(), checksum)
+	}
+
+	updated, err := c.download(url, ref, entry)
+	switch {
+	case err != nil && cached:
+		fmt.Printf("  ⚠️  模板更新检查失败，使用缓存（%s 获取）: %v\n", entry.FetchedAt.Format("2006-01-02 15:04"), err)
+		return entry, verifyFileChecksum(entry.ArchivePath(), checksum)
+	case err != nil:
+		return nil, err
+	case updated == entry:
+		fmt.Println("  ✓ 模板未变化，使用缓存")
+	case cached:
+		fmt.Println("  ✓ 模板有更新，已刷新缓存")
+	}
+	if err := verifyFileChecksum(updated.ArchivePath(), checksum); err != nil {
+		// 刚下载且校验失败的归档不保留在缓存中；未变化的已有条目保留
+		if updated != entry {
+			os.RemoveAll(updated.dir)
+		}
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Update 重新校验所有缓存条目，返回内容有变化的条目
+func (c *TemplateCache) Update() ([]CacheEntry, error) {
+	entries, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []CacheEntry
+	for i := range entries {
+		entry := &entries[i]
+		if commitSHA.MatchString(entry.Ref) {
+			continue
+		}
+		updated, err := c.download(entry.URL, entry.Ref, entry)
+		if err != nil {
+			return changed, fmt.Errorf("更新 %s 失败: %w", entry.Label(), err)
+		}
+		if updated != entry {
+			changed = append(changed, *updated)
+		}
+	}
+	return changed, nil
+}
+
+// List 列出所有缓存条目（按地址排序）
+func (c *TemplateCache) List() ([]CacheEntry, error) {
+	dirs, err := os.ReadDir(c.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CacheEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := readCacheEntry(filepath.Join(c.Dir, d.Name()))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Label() < entries[j].Label()
+	})
+	return entries, nil
+}
+
+// Clean 删除缓存条目，url 为空时删除全部，返回删除的条目数
+func (c *TemplateCache) Clean(url string) (int, error) {
+	entries, err := c.List()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, e := range entries {
+		if url != "" && e.URL != url {
+			continue
+		}
+		if err := os.RemoveAll(e.dir); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// lookup 查找缓存条目
+func (c *TemplateCache) lookup(url, ref string) (*CacheEntry, bool) {
+	entry, err := readCacheEntry(c.entryDir(url, ref))
+	if err != nil {
+		return nil, false
+	}
+	if _, err := os.Stat(entry.ArchivePath()); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+// entryDir 缓存条目目录，以 地址@版本 的哈希命名
+func (c *TemplateCache) entryDir(url, ref string) string {
+	sum := sha256.Sum256([]byte(url + "@" + ref))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:8]))
+}
+
+// download 下载归档到缓存；current 非空时携带 If-None-Match，未变化时原样返回 current
+func (c *TemplateCache) download(url, ref string, current *CacheEntry) (*CacheEntry, error) {
+	resolved, err := ResolveTemplateURL(url, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := c.entryDir(url, ref)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	etag := ""
+	if current != nil {
+		etag = current.ETag
+	}
+	tmp := filepath.Join(dir, cacheArchiveFile+".tmp")
+	defer os.Remove(tmp)
+
+	d, err := newDownloader(c.Options)
+	if err != nil {
+		return nil, err
+	}
+	result, err := d.download(resolved, tmp, etag)
+	if err != nil {
+		return nil, err
+	}
+	if result.NotModified {
+		return current, nil
+	}
+
+	sum, size, err := fileSHA256(tmp)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, cacheArchiveFile)); err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{
+		URL:       url,
+		Ref:       ref,
+		ETag:      result.ETag,
+		SHA256:    sum,
+		Size:      size,
+		FetchedAt: time.Now(),
+		dir:       dir,
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, cacheMetaFile), data, 0644); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// readCacheEntry 读取缓存条目元信息
+func readCacheEntry(dir string) (*CacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, cacheMetaFile))
+	if err != nil {
+		return nil, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	entry.dir = dir
+	return &entry, nil
+}
+
+// fileSHA256 计算文件的 SHA-256 与大小
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// verifyChecksum 校验 SHA-256，expected 为空时不校验
+func verifyChecksum(actual, expected string) error {
+	expected = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(expected, "sha256:")))
+	if expected == "" || expected == actual {
+		return nil
+	}
+	return fmt.Errorf("模板校验失败: SHA-256 为 %s，期望 %s", actual, expected)
+}
+
+// verifyFileChecksum 校验文件的 SHA-256，expected 为空时不校验
+func verifyFileChecksum(path, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	sum, _, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	return verifyChecksum(sum, expected)
+}
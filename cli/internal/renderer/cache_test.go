@@ -0,0 +1,190 @@
+package renderer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestResolveTemplateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{url: DefaultTemplateURL, want: DefaultTemplateURL},
+		{url: DefaultTemplateURL, ref: "v1.2.0", want: "https://github.com/richer421/ai_skeleton/archive/v1.2.0.zip"},
+		{url: "https://github.com/org/repo/archive/refs/heads/main.zip", ref: "abc123", want: "https://github.com/org/repo/archive/abc123.zip"},
+		{url: "https://git.example.com/org/repo/-/archive/{ref}/repo.zip", ref: "v2", want: "https://git.example.com/org/repo/-/archive/v2/repo.zip"},
+		{url: "https://example.com/template.zip", ref: "v1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveTemplateURL(tt.url, tt.ref)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ResolveTemplateURL(%s, %s) err = %v", tt.url, tt.ref, err)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveTemplateURL(%s, %s) = %s, want %s", tt.url, tt.ref, got, tt.want)
+		}
+	}
+}
+
+// templateServer 返回固定内容并支持 ETag 校验的模板服务
+func templateServer(t *testing.T, body *string, downloads *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(*body))
+		etag := `"` + hex.EncodeToString(sum[:4]) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(downloads, 1)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(*body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestTemplateCache_Fetch(t *testing.T) {
+	body := "v1"
+	var downloads int32
+	srv := templateServer(t, &body, &downloads)
+	cache := &TemplateCache{Dir: t.TempDir()}
+	url := srv.URL + "/archive/{ref}.zip"
+
+	entry, err := cache.Fetch(url, "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(entry.ArchivePath())
+	if string(data) != "v1" || downloads != 1 {
+		t.Fatalf("首次获取: 内容 %q, 下载 %d 次", data, downloads)
+	}
+
+	// 未变化时使用缓存
+	if _, err := cache.Fetch(url, "main", ""); err != nil || downloads != 1 {
+		t.Fatalf("重新校验: err = %v, 下载 %d 次", err, downloads)
+	}
+
+	// 内容变化后重新下载
+	body = "v2"
+	entry, err = cache.Fetch(url, "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(entry.ArchivePath())
+	if string(data) != "v2" || downloads != 2 {
+		t.Fatalf("更新后: 内容 %q, 下载 %d 次", data, downloads)
+	}
+
+	// 不同版本分别缓存
+	if _, err := cache.Fetch(url, "v1.0.0", ""); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := cache.List()
+	if len(entries) != 2 {
+		t.Fatalf("缓存条目数 = %d, want 2", len(entries))
+	}
+
+	// 服务不可用时退回到缓存
+	srv.Close()
+	if _, err := cache.Fetch(url, "main", ""); err != nil {
+		t.Fatalf("离线时应使用缓存: %v", err)
+	}
+}
+
+func TestTemplateCache_Checksum(t *testing.T) {
+	body := "archive"
+	var downloads int32
+	srv := templateServer(t, &body, &downloads)
+	cache := &TemplateCache{Dir: t.TempDir()}
+
+	sum := sha256.Sum256([]byte(body))
+	if _, err := cache.Fetch(srv.URL+"/a.zip", "", "sha256:"+hex.EncodeToString(sum[:])); err != nil {
+		t.Fatalf("校验和匹配时不应报错: %v", err)
+	}
+
+	cache = &TemplateCache{Dir: t.TempDir()}
+	if _, err := cache.Fetch(srv.URL+"/a.zip", "", "deadbeef"); err == nil {
+		t.Fatal("校验和不匹配时应报错")
+	}
+	if entries, _ := cache.List(); len(entries) != 0 {
+		t.Fatal("校验失败的归档不应保留在缓存中")
+	}
+}
+
+func TestTemplateCache_ChecksumCached(t *testing.T) {
+	body := "archive"
+	var downloads int32
+	srv := templateServer(t, &body, &downloads)
+	cache := &TemplateCache{Dir: t.TempDir()}
+	sum := sha256.Sum256([]byte(body))
+	checksum := hex.EncodeToString(sum[:])
+
+	// 未变化（304）时校验和错误：报错但保留已有缓存
+	url := srv.URL + "/a.zip"
+	if _, err := cache.Fetch(url, "", checksum); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Fetch(url, "", "deadbeef"); err == nil {
+		t.Fatal("校验和不匹配时应报错")
+	}
+	if entries, _ := cache.List(); len(entries) != 1 {
+		t.Fatal("未变化的缓存条目不应因校验失败被删除")
+	}
+	if _, err := cache.Fetch(url, "", checksum); err != nil || downloads != 1 {
+		t.Fatalf("缓存应仍可用: err = %v, 下载 %d 次", err, downloads)
+	}
+
+	// 命中固定 commit 的缓存时校验磁盘上的文件，而非元信息中记录的哈希
+	ref := strings.Repeat("a", 40)
+	entry, err := cache.Fetch(srv.URL+"/archive/{ref}.zip", ref, checksum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(entry.ArchivePath(), []byte("tampered"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Fetch(srv.URL+"/archive/{ref}.zip", ref, checksum); err == nil {
+		t.Fatal("缓存文件被篡改时应校验失败")
+	}
+}
+
+func TestTemplateCache_UpdateAndClean(t *testing.T) {
+	body := "v1"
+	var downloads int32
+	srv := templateServer(t, &body, &downloads)
+	cache := &TemplateCache{Dir: t.TempDir()}
+
+	cache.Fetch(srv.URL+"/a.zip", "", "")
+	cache.Fetch(srv.URL+"/b.zip", "", "")
+
+	changed, err := cache.Update()
+	if err != nil || len(changed) != 0 {
+		t.Fatalf("未变化时 Update() = %v, %v", changed, err)
+	}
+
+	body = "v2"
+	changed, err = cache.Update()
+	if err != nil || len(changed) != 2 {
+		t.Fatalf("变化后 Update() = %v, %v", changed, err)
+	}
+
+	removed, err := cache.Clean(srv.URL + "/a.zip")
+	if err != nil || removed != 1 {
+		t.Fatalf("Clean(url) = %d, %v", removed, err)
+	}
+	removed, _ = cache.Clean("")
+	if removed != 1 {
+		t.Fatalf("Clean() = %d, want 1", removed)
+	}
+}
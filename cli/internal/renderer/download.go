@@ -0,0 +1,308 @@
+package renderer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TemplateTokenEnv 访问私有模板仓库的令牌，未指定 --token 时使用
+const TemplateTokenEnv = "AIS_TEMPLATE_TOKEN"
+
+// defaultDownloadTimeout 未指定 --timeout 时单次下载的超时时间
+const defaultDownloadTimeout = 10 * time.Minute
+
+// maxRedirects 最多跟随的重定向次数
+const maxRedirects = 10
+
+// progressInterval 下载进度的刷新间隔
+const progressInterval = 100 * time.Millisecond
+
+// DownloadOptions 下载远程模板的选项
+type DownloadOptions struct {
+	Token    string        // 访问令牌，为空时依次使用 AIS_TEMPLATE_TOKEN 与 ~/.netrc
+	Proxy    string        // 代理地址，为空时使用 HTTPS_PROXY 等环境变量
+	CACert   string        // 额外信任的 CA 证书（PEM），用于自签名证书的私有仓库
+	Timeout  time.Duration // 单次下载的超时时间，为 0 时使用默认值
+	Retries  int           // 网络错误或服务端错误时的重试次数
+	Progress io.Writer     // 下载进度输出，为空时不显示
+}
+
+// retryDelay 第 n 次重试前的等待时间（1s、2s、4s...）
+var retryDelay = func(n int) time.Duration {
+	return time.Second << (n - 1)
+}
+
+// downloader 按选项配置的 HTTP 下载器
+type downloader struct {
+	opts   DownloadOptions
+	client *http.Client
+}
+
+// newDownloader 根据选项创建下载器：配置代理、CA 证书、超时，跨域重定向时不携带令牌
+func newDownloader(opts DownloadOptions) (*downloader, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("代理地址 %s 无效", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CACert != "" {
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("读取 CA 证书失败: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s 中没有有效的 PEM 证书", opts.CACert)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultDownloadTimeout
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("重定向次数过多")
+			}
+			if req.URL.Host != via[0].URL.Host {
+				req.Header.Del("Authorization")
+				req.Header.Del("PRIVATE-TOKEN")
+			}
+			return nil
+		},
+	}
+	return &downloader{opts: opts, client: client}, nil
+}
+
+// downloadResult 下载结果
+type downloadResult struct {
+	NotModified bool
+	ETag        string
+}
+
+// statusError 服务端返回的错误状态码
+type statusError struct {
+	Code int
+}
+
+func (e *statusError) Error() string {
+	switch e.Code {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Sprintf("下载失败: 状态码 %d，请通过 --token、%s 或 ~/.netrc 提供有权限的访问令牌", e.Code, TemplateTokenEnv)
+	case http.StatusNotFound:
+		return "下载失败: 状态码 404，模板不存在或无权访问（私有仓库需要提供访问令牌）"
+	}
+	return fmt.Sprintf("下载失败: 状态码 %d", e.Code)
+}
+
+// retryable 网络错误、限流与服务端错误可以重试
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	return true
+}
+
+// download 下载文件，失败时按 Retries 重试；etag 非空时携带 If-None-Match，服务端返回 304 时不写入文件
+func (d *downloader) download(rawURL, dst, etag string) (*downloadResult, error) {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var result *downloadResult
+		if result, err = d.fetch(rawURL, dst, etag); err == nil {
+			return result, nil
+		}
+		if attempt >= d.opts.Retries || !retryable(err) {
+			return nil, err
+		}
+		delay := retryDelay(attempt + 1)
+		fmt.Printf("  ⚠️  %v，%s 后重试（%d/%d）\n", err, delay, attempt+1, d.opts.Retries)
+		time.Sleep(delay)
+	}
+}
+
+// fetch 发起一次下载
+func (d *downloader) fetch(rawURL, dst, etag string) (*downloadResult, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	d.authorize(req)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return &downloadResult{NotModified: true, ETag: etag}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &statusError{Code: resp.StatusCode}
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+
+	var w io.Writer = out
+	if d.opts.Progress != nil {
+		p := &progressWriter{w: d.opts.Progress, total: resp.ContentLength}
+		defer p.finish()
+		w = io.MultiWriter(out, p)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return nil, err
+	}
+	return &downloadResult{ETag: resp.Header.Get("ETag")}, out.Close()
+}
+
+// authorize 设置认证信息：--token > AIS_TEMPLATE_TOKEN > ~/.netrc
+func (d *downloader) authorize(req *http.Request) {
+	token := d.opts.Token
+	if token == "" {
+		token = os.Getenv(TemplateTokenEnv)
+	}
+	if token != "" {
+		key, value := authHeader(req.URL, token)
+		req.Header.Set(key, value)
+		return
+	}
+	if login, password, ok := netrcLookup(req.URL.Hostname()); ok {
+		req.SetBasicAuth(login, password)
+	}
+}
+
+// authHeader 按仓库类型选择令牌的请求头：GitLab API 使用 PRIVATE-TOKEN，Gitea API 使用 token 前缀，其余使用 Bearer
+func authHeader(u *url.URL, token string) (string, string) {
+	switch {
+	case strings.Contains(u.Path, "/api/v4/"):
+		return "PRIVATE-TOKEN", token
+	case strings.Contains(u.Path, "/api/v1/"):
+		return "Authorization", "token " + token
+	}
+	return "Authorization", "Bearer " + token
+}
+
+// netrcLookup 在 $NETRC（默认 ~/.netrc）中查找主机的登录信息
+func netrcLookup(host string) (string, string, bool) {
+	path := os.Getenv("NETRC")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", false
+		}
+		path = filepath.Join(home, ".netrc")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false
+	}
+	return parseNetrc(string(data), host)
+}
+
+// parseNetrc 解析 netrc，返回第一个与 host 匹配的 machine（或 default）中的 login 与 password
+func parseNetrc(data, host string) (string, string, bool) {
+	var login, password string
+	matched, found := false, false
+	fields := strings.Fields(data)
+	for i := 0; i < len(fields); i++ {
+		switch fields[i] {
+		case "machine", "default":
+			if found {
+				return login, password, true
+			}
+			matched = fields[i] == "default"
+			if fields[i] == "machine" && i+1 < len(fields) {
+				i++
+				matched = fields[i] == host
+			}
+		case "login", "password", "account":
+			if i+1 < len(fields) && matched {
+				found = true
+				switch fields[i] {
+				case "login":
+					login = fields[i+1]
+				case "password":
+					password = fields[i+1]
+				}
+			}
+			i++
+		case "macdef":
+			// 宏定义位于文件末尾，之后不再有登录信息
+			return login, password, found
+		}
+	}
+	return login, password, found
+}
+
+// progressWriter 输出下载进度，最多每 100ms 刷新一次
+type progressWriter struct {
+	w     io.Writer
+	total int64 // 总大小，未知时为 -1
+	done  int64
+	last  time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if time.Since(p.last) >= progressInterval {
+		p.print()
+	}
+	return len(b), nil
+}
+
+// print 刷新当前进度行
+func (p *progressWriter) print() {
+	p.last = time.Now()
+	if p.total > 0 {
+		fmt.Fprintf(p.w, "\r  ⬇️  下载中 %s / %s（%d%%）", formatSize(p.done), formatSize(p.total), p.done*100/p.total)
+		return
+	}
+	fmt.Fprintf(p.w, "\r  ⬇️  下载中 %s", formatSize(p.done))
+}
+
+// finish 输出最终进度并换行
+func (p *progressWriter) finish() {
+	p.print()
+	fmt.Fprintln(p.w)
+}
+
+// formatSize 以 KB/MB 显示大小
+func formatSize(n int64) string {
+	if n >= 1<<20 {
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
+
+// isTerminal 判断文件是否为终端，非终端（如重定向到文件）时不显示下载进度
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,222 @@
+package renderer
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExpandTemplateURL(t *testing.T) {
+	tests := []struct {
+		raw, ref string
+		wantURL  string
+		wantRef  string
+		wantErr  bool
+	}{
+		{raw: DefaultTemplateURL, wantURL: DefaultTemplateURL},
+		{raw: "https://example.com/{ref}.zip", ref: "v1", wantURL: "https://example.com/{ref}.zip", wantRef: "v1"},
+		{raw: "gh:org/repo", wantURL: "https://api.github.com/repos/org/repo/zipball"},
+		{raw: "gh:org/repo@v1.2.0", wantURL: "https://api.github.com/repos/org/repo/zipball/{ref}", wantRef: "v1.2.0"},
+		{raw: "gh:org/repo", ref: "main", wantURL: "https://api.github.com/repos/org/repo/zipball/{ref}", wantRef: "main"},
+		{raw: "gh:github.example.com/org/repo@v1", wantURL: "https://github.example.com/api/v3/repos/org/repo/zipball/{ref}", wantRef: "v1"},
+		{raw: "gl:group/sub/repo@v2", wantURL: "https://gitlab.com/api/v4/projects/group%2Fsub%2Frepo/repository/archive.zip?sha={ref}", wantRef: "v2"},
+		{raw: "gl:git.example.com/group/repo", wantURL: "https://git.example.com/api/v4/projects/group%2Frepo/repository/archive.zip"},
+		{raw: "gitea:org/repo@v3", wantURL: "https://gitea.com/api/v1/repos/org/repo/archive/{ref}.zip", wantRef: "v3"},
+		{raw: "gitea:org/repo", wantURL: "https://gitea.com/api/v1/repos/org/repo/archive/main.zip"},
+		{raw: "gh:org/repo@v1", ref: "v2", wantErr: true},
+		{raw: "gh:org/repo@", wantErr: true},
+		{raw: "gh:repo", wantErr: true},
+		{raw: "gh:org/sub/repo", wantErr: true},
+		{raw: "gh:org//repo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			gotURL, gotRef, err := ExpandTemplateURL(tt.raw, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotURL != tt.wantURL || gotRef != tt.wantRef {
+				t.Errorf("ExpandTemplateURL() = %s, %s, want %s, %s", gotURL, gotRef, tt.wantURL, tt.wantRef)
+			}
+		})
+	}
+}
+
+func TestParseNetrc(t *testing.T) {
+	data := `machine git.example.com
+  login alice
+  password s3cret
+default login anonymous password guest
+macdef init
+  machine evil.com login x password y
+`
+	tests := []struct {
+		host            string
+		login, password string
+		ok              bool
+	}{
+		{host: "git.example.com", login: "alice", password: "s3cret", ok: true},
+		{host: "other.com", login: "anonymous", password: "guest", ok: true},
+		{host: "evil.com", login: "anonymous", password: "guest", ok: true},
+	}
+	for _, tt := range tests {
+		login, password, ok := parseNetrc(data, tt.host)
+		if login != tt.login || password != tt.password || ok != tt.ok {
+			t.Errorf("parseNetrc(%s) = %s, %s, %v", tt.host, login, password, ok)
+		}
+	}
+	if _, _, ok := parseNetrc("machine a.com login x password y\n", "b.com"); ok {
+		t.Error("没有匹配的 machine 时不应返回登录信息")
+	}
+}
+
+func TestDownloader_Auth(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte("zip"))
+	}))
+	defer srv.Close()
+
+	netrc := filepath.Join(t.TempDir(), ".netrc")
+	os.WriteFile(netrc, []byte("machine 127.0.0.1 login bob password pw\n"), 0600)
+	t.Setenv("NETRC", netrc)
+
+	tests := []struct {
+		name   string
+		token  string
+		env    string
+		path   string
+		header string
+		want   string
+	}{
+		{name: "--token", token: "t1", env: "t2", path: "/repos/o/r/zipball", header: "Authorization", want: "Bearer t1"},
+		{name: "环境变量", env: "t2", path: "/repos/o/r/zipball", header: "Authorization", want: "Bearer t2"},
+		{name: "GitLab", token: "t1", path: "/api/v4/projects/o%2Fr/repository/archive.zip", header: "PRIVATE-TOKEN", want: "t1"},
+		{name: "Gitea", token: "t1", path: "/api/v1/repos/o/r/archive/main.zip", header: "Authorization", want: "token t1"},
+		{name: "netrc", path: "/a.zip", header: "Authorization", want: "Basic Ym9iOnB3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(TemplateTokenEnv, tt.env)
+			d, err := newDownloader(DownloadOptions{Token: tt.token})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := d.download(srv.URL+tt.path, filepath.Join(t.TempDir(), "a.zip"), ""); err != nil {
+				t.Fatal(err)
+			}
+			if v := got.Get(tt.header); v != tt.want {
+				t.Errorf("%s = %q, want %q", tt.header, v, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloader_RedirectDropsToken(t *testing.T) {
+	var leaked atomic.Value
+	leaked.Store("")
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked.Store(r.Header.Get("PRIVATE-TOKEN") + r.Header.Get("Authorization"))
+		w.Write([]byte("zip"))
+	}))
+	defer storage.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, storage.URL+"/archive.zip", http.StatusFound)
+	}))
+	defer api.Close()
+
+	d, _ := newDownloader(DownloadOptions{Token: "secret"})
+	if _, err := d.download(api.URL+"/api/v4/projects/1/repository/archive.zip", filepath.Join(t.TempDir(), "a.zip"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if v := leaked.Load().(string); v != "" {
+		t.Errorf("重定向到其他主机时不应携带令牌: %q", v)
+	}
+}
+
+func TestDownloader_Retry(t *testing.T) {
+	delay := retryDelay
+	retryDelay = func(int) time.Duration { return 0 }
+	defer func() { retryDelay = delay }()
+
+	tests := []struct {
+		name      string
+		fail      int // 前几次请求返回 status
+		status    int
+		retries   int
+		wantCalls int32
+		wantErr   string
+	}{
+		{name: "服务端错误后重试成功", fail: 2, status: http.StatusServiceUnavailable, retries: 3, wantCalls: 3},
+		{name: "超过重试次数", fail: 5, status: http.StatusBadGateway, retries: 2, wantCalls: 3, wantErr: "状态码 502"},
+		{name: "404 不重试", fail: 5, status: http.StatusNotFound, retries: 3, wantCalls: 1, wantErr: "无权访问"},
+		{name: "401 提示提供令牌", fail: 5, status: http.StatusUnauthorized, retries: 3, wantCalls: 1, wantErr: TemplateTokenEnv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if int(atomic.AddInt32(&calls, 1)) <= tt.fail {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte("zip"))
+			}))
+			defer srv.Close()
+
+			d, _ := newDownloader(DownloadOptions{Retries: tt.retries})
+			_, err := d.download(srv.URL, filepath.Join(t.TempDir(), "a.zip"), "")
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("请求 %d 次，want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDownloader_Progress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "4096")
+		w.Write(bytes.Repeat([]byte("x"), 4096))
+	}))
+	defer srv.Close()
+
+	var out bytes.Buffer
+	d, _ := newDownloader(DownloadOptions{Progress: &out})
+	if _, err := d.download(srv.URL, filepath.Join(t.TempDir(), "a.zip"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "下载中 4.0 KB / 4.0 KB（100%）") {
+		t.Errorf("进度输出 = %q", out.String())
+	}
+}
+
+func TestNewDownloader_Invalid(t *testing.T) {
+	badPEM := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(badPEM, []byte("not a certificate"), 0644)
+
+	tests := []struct {
+		name string
+		opts DownloadOptions
+	}{
+		{name: "代理地址无效", opts: DownloadOptions{Proxy: "://bad"}},
+		{name: "CA 证书不存在", opts: DownloadOptions{CACert: filepath.Join(t.TempDir(), "missing.pem")}},
+		{name: "CA 证书格式错误", opts: DownloadOptions{CACert: badPEM}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newDownloader(tt.opts); err == nil {
+				t.Error("应返回错误")
+			}
+		})
+	}
+}
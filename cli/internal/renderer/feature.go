@@ -0,0 +1,431 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// Feature 可选功能，未启用时删除其文件、配置段、go.mod 依赖、Makefile 目标与代码块
+//
+// 代码块以注释标记，功能名可用 | 连接（任一启用即保留）：
+//
+//	// ais:feature mcp
+//	setupMCP(r, v1)
+//	// ais:end
+//
+//	"github.com/x/internal/database" // ais:feature mysql|redis
+type Feature struct {
+	Name        string              `yaml:"name"`
+	Description string              `yaml:"description"`
+	Default     bool                `yaml:"default"`  // 默认是否启用（交互式选择的默认值，非交互模式下直接使用）
+	Depends     []string            `yaml:"depends"`  // 依赖的其他功能
+	Files       []string            `yaml:"files"`    // 属于该功能的文件（glob）
+	Config      map[string][]string `yaml:"config"`   // YAML 配置文件 → 配置段（如 database.mysql）
+	Requires    map[string][]string `yaml:"requires"` // go.mod → 模块路径
+	Makefile    map[string][]string `yaml:"makefile"` // Makefile → 目标
+}
+
+// validateFeatures 校验功能名唯一且依赖已声明
+func (m *Manifest) validateFeatures() error {
+	seen := make(map[string]bool, len(m.Features))
+	for _, f := range m.Features {
+		if f.Name == "" {
+			return fmt.Errorf("%s: 功能缺少 name", ManifestFile)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%s: 功能 %s 重复声明", ManifestFile, f.Name)
+		}
+		seen[f.Name] = true
+	}
+	for _, f := range m.Features {
+		for _, d := range f.Depends {
+			if !seen[d] {
+				return fmt.Errorf("%s: 功能 %s 依赖未声明的功能 %s", ManifestFile, f.Name, d)
+			}
+		}
+	}
+	return nil
+}
+
+// FeatureNames 模板声明的功能名
+func (m *Manifest) FeatureNames() []string {
+	names := make([]string, 0, len(m.Features))
+	for _, f := range m.Features {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// ResolveFeatures 确定启用的功能：已指定时校验名称与依赖，否则交互式选择（非交互模式下使用默认值）
+func (m *Manifest) ResolveFeatures(meta *ProjectMeta) error {
+	if len(m.Features) == 0 {
+		if meta.Features != nil {
+			fmt.Println("  ⚠️  模板未声明可选功能，忽略 --features")
+			meta.Features = nil
+		}
+		return nil
+	}
+
+	enabled := make(map[string]bool)
+	switch {
+	case meta.Features == nil && meta.NonInteractive:
+		m.defaultFeatures(enabled)
+	case meta.Features == nil:
+		if err := m.promptFeatures(enabled); err != nil {
+			return err
+		}
+	default:
+		for _, name := range meta.Features {
+			if !contains(m.FeatureNames(), name) {
+				return fmt.Errorf("未知的功能 %s，可选：%s", name, strings.Join(m.FeatureNames(), ", "))
+			}
+			enabled[name] = true
+		}
+		for _, f := range m.Features {
+			for _, d := range f.Depends {
+				if enabled[f.Name] && !enabled[d] {
+					return fmt.Errorf("功能 %s 依赖 %s，请同时启用", f.Name, d)
+				}
+			}
+		}
+	}
+
+	// 按清单中的顺序记录启用的功能
+	meta.Features = make([]string, 0, len(enabled))
+	for _, f := range m.Features {
+		if enabled[f.Name] {
+			meta.Features = append(meta.Features, f.Name)
+		}
+	}
+	return nil
+}
+
+// defaultFeatures 启用默认开启且依赖已满足的功能
+func (m *Manifest) defaultFeatures(enabled map[string]bool) {
+	for _, f := range m.Features {
+		if f.Default && dependsSatisfied(f, enabled) {
+			enabled[f.Name] = true
+		}
+	}
+}
+
+// promptFeatures 逐个确认是否启用功能，依赖未启用的功能直接跳过
+func (m *Manifest) promptFeatures(enabled map[string]bool) error {
+	for _, f := range m.Features {
+		if !dependsSatisfied(f, enabled) {
+			continue
+		}
+
+		label := "启用 " + f.Name
+		if f.Description != "" {
+			label += "（" + f.Description + "）"
+		}
+		def := "n"
+		if f.Default {
+			def = "y"
+		}
+		prompt := promptui.Prompt{Label: label, IsConfirm: true, Default: def}
+		_, err := prompt.Run()
+		switch err {
+		case nil:
+			enabled[f.Name] = true
+		case promptui.ErrAbort:
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// dependsSatisfied 功能依赖的其他功能是否均已启用
+func dependsSatisfied(f Feature, enabled map[string]bool) bool {
+	for _, d := range f.Depends {
+		if !enabled[d] {
+			return false
+		}
+	}
+	return true
+}
+
+// HasFeature 功能是否启用；模板未声明功能时视为全部启用
+func (meta *ProjectMeta) HasFeature(name string) bool {
+	return meta.Features == nil || contains(meta.Features, name)
+}
+
+// featureSet 启用的功能集合与模板声明的全部功能
+type featureSet struct {
+	declared []Feature
+	enabled  map[string]bool
+}
+
+func newFeatureSet(m *Manifest, meta *ProjectMeta) featureSet {
+	fs := featureSet{declared: m.Features, enabled: make(map[string]bool)}
+	for _, f := range m.Features {
+		fs.enabled[f.Name] = meta.HasFeature(f.Name)
+	}
+	return fs
+}
+
+// skip 文件所属的未启用功能，不属于时返回空字符串
+func (fs featureSet) skip(rel string) string {
+	for _, f := range fs.declared {
+		if !fs.enabled[f.Name] && matchAny(f.Files, rel) {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// apply 删除未启用功能的代码块、配置段、go.mod 依赖与 Makefile 目标
+func (fs featureSet) apply(rel string, content []byte) ([]byte, error) {
+	if len(fs.declared) == 0 {
+		return content, nil
+	}
+	if bytes.Contains(content, []byte("ais:")) {
+		var err error
+		if content, err = fs.stripBlocks(rel, content); err != nil {
+			return nil, err
+		}
+	}
+
+	text := string(content)
+	for _, f := range fs.declared {
+		if fs.enabled[f.Name] {
+			continue
+		}
+		for _, key := range f.Config[rel] {
+			text = removeYAMLKey(text, key)
+		}
+		for _, mod := range f.Requires[rel] {
+			text = removeGoRequire(text, mod)
+		}
+		for _, target := range f.Makefile[rel] {
+			text = removeMakeTarget(text, target)
+		}
+	}
+	return []byte(text), nil
+}
+
+var (
+	featureBeginRe  = regexp.MustCompile(`^\s*(//|#)\s*ais:feature\s+([\w|-]+)\s*$`)
+	featureEndRe    = regexp.MustCompile(`^\s*(//|#)\s*ais:end\s*$`)
+	featureInlineRe = regexp.MustCompile(`\s*(//|#)\s*ais:feature\s+([\w|-]+)\s*$`)
+)
+
+// stripBlocks 处理功能标记：保留已启用功能的代码（去掉标记），删除未启用功能的代码
+func (fs featureSet) stripBlocks(rel string, content []byte) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	out := make([]string, 0, len(lines))
+	var stack []bool // 外层代码块是否保留
+
+	keep := func() bool {
+		for _, k := range stack {
+			if !k {
+				return false
+			}
+		}
+		return true
+	}
+
+	for i, line := range lines {
+		if m := featureBeginRe.FindStringSubmatch(line); m != nil {
+			on, err := fs.anyEnabled(m[2])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", rel, i+1, err)
+			}
+			stack = append(stack, on)
+			continue
+		}
+		if featureEndRe.MatchString(line) {
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%s:%d: ais:end 没有对应的 ais:feature", rel, i+1)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if !keep() {
+			continue
+		}
+		if m := featureInlineRe.FindStringSubmatchIndex(line); m != nil && strings.TrimSpace(line[:m[0]]) != "" {
+			on, err := fs.anyEnabled(line[m[4]:m[5]])
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", rel, i+1, err)
+			}
+			if !on {
+				continue
+			}
+			line = line[:m[0]]
+		}
+		out = append(out, line)
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("%s: ais:feature 缺少对应的 ais:end", rel)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+// anyEnabled names 中任一功能启用即返回 true
+func (fs featureSet) anyEnabled(names string) (bool, error) {
+	on := false
+	for _, name := range strings.Split(names, "|") {
+		enabled, ok := fs.enabled[name]
+		if !ok {
+			return false, fmt.Errorf("未声明的功能 %s", name)
+		}
+		on = on || enabled
+	}
+	return on, nil
+}
+
+// removeYAMLKey 按缩进删除 YAML 中的配置段（如 database.mysql），连同紧邻的注释；父级因此为空时一并删除
+func removeYAMLKey(content, key string) string {
+	path := strings.Split(key, ".")
+	lines := strings.Split(content, "\n")
+
+	keyLine, indent, end, ok := findYAMLBlock(lines, path)
+	if !ok {
+		return content
+	}
+	start := keyLine
+	for start > 0 && isYAMLComment(lines[start-1]) && indentOf(lines[start-1]) == indent {
+		start--
+	}
+	lines = removeLines(lines, start, end)
+
+	if len(path) > 1 {
+		parentLine, _, parentEnd, ok := findYAMLBlock(lines, path[:len(path)-1])
+		if ok && !hasYAMLContent(lines[parentLine+1:parentEnd]) {
+			return removeYAMLKey(strings.Join(lines, "\n"), strings.Join(path[:len(path)-1], "."))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// findYAMLBlock 查找配置段：返回键所在行、缩进与块结束位置（不含末尾空行）
+func findYAMLBlock(lines []string, path []string) (keyLine, indent, end int, ok bool) {
+	lo, hi, parentIndent := 0, len(lines), -1
+	for _, k := range path {
+		keyLine, indent = -1, -1
+		for i := lo; i < hi; i++ {
+			if isBlank(lines[i]) || isYAMLComment(lines[i]) {
+				continue
+			}
+			ind := indentOf(lines[i])
+			if ind <= parentIndent {
+				break
+			}
+			if indent < 0 {
+				indent = ind
+			}
+			if ind == indent && strings.HasPrefix(strings.TrimSpace(lines[i]), k+":") {
+				keyLine = i
+				break
+			}
+		}
+		if keyLine < 0 {
+			return 0, 0, 0, false
+		}
+
+		end = keyLine + 1
+		for end < len(lines) && (isBlank(lines[end]) || indentOf(lines[end]) > indent) {
+			end++
+		}
+		for end > keyLine+1 && isBlank(lines[end-1]) {
+			end--
+		}
+		lo, hi, parentIndent = keyLine+1, end, indent
+	}
+	return keyLine, indent, end, true
+}
+
+// hasYAMLContent 是否包含注释以外的内容
+func hasYAMLContent(lines []string) bool {
+	for _, line := range lines {
+		if !isBlank(line) && !isYAMLComment(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeGoRequire 删除 go.mod 中的 require 项
+func removeGoRequire(content, module string) string {
+	lines := strings.Split(content, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == module ||
+			len(fields) >= 3 && fields[0] == "require" && fields[1] == module {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// removeMakeTarget 删除 Makefile 目标（连同上方注释与命令），并从 .PHONY 中移除
+func removeMakeTarget(content, target string) string {
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, ".PHONY:") {
+			fields := strings.Fields(strings.TrimPrefix(line, ".PHONY:"))
+			kept := fields[:0]
+			for _, f := range fields {
+				if f != target {
+					kept = append(kept, f)
+				}
+			}
+			lines[i] = strings.TrimSpace(".PHONY: " + strings.Join(kept, " "))
+			continue
+		}
+		if !strings.HasPrefix(line, target+":") {
+			continue
+		}
+
+		start, end := i, i+1
+		for start > 0 && strings.HasPrefix(lines[start-1], "#") {
+			start--
+		}
+		for end < len(lines) && strings.HasPrefix(lines[end], "\t") {
+			end++
+		}
+		lines = removeLines(lines, start, end)
+		i = start - 1
+	}
+	return strings.Join(lines, "\n")
+}
+
+// removeLines 删除 [start, end) 行，并合并因此相邻的空行
+func removeLines(lines []string, start, end int) []string {
+	out := append(lines[:start:start], lines[end:]...)
+	if start > 0 && isBlank(out[start-1]) && (start == len(out) || isBlank(out[start])) {
+		out = append(out[:start-1], out[start:]...)
+	}
+	return out
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func isYAMLComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testConfig = `# 项目信息
+project:
+  name: "demo"
+
+# 数据库配置
+database:
+  mysql:
+    host: "127.0.0.1"
+    port: 3306
+  redis:
+    host: "127.0.0.1"
+    # db: 0
+
+# MCP 配置
+mcp:
+  enabled: true
+  auth:
+    enabled: false
+    api_keys: []
+    #  - name: "ci"
+  audit:
+    enabled: true
+`
+
+func TestRemoveYAMLKey(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want string
+	}{
+		{
+			name: "删除子配置段",
+			keys: []string{"mcp.auth"},
+			want: strings.Replace(testConfig, "  auth:\n    enabled: false\n    api_keys: []\n    #  - name: \"ci\"\n", "", 1),
+		},
+		{
+			name: "父级为空时一并删除",
+			keys: []string{"database.mysql", "database.redis"},
+			want: "# 项目信息\nproject:\n  name: \"demo\"\n\n# MCP 配置\nmcp:\n  enabled: true\n  auth:\n    enabled: false\n    api_keys: []\n    #  - name: \"ci\"\n  audit:\n    enabled: true\n",
+		},
+		{
+			name: "删除末尾的顶级配置段",
+			keys: []string{"mcp"},
+			want: "# 项目信息\nproject:\n  name: \"demo\"\n\n# 数据库配置\ndatabase:\n  mysql:\n    host: \"127.0.0.1\"\n    port: 3306\n  redis:\n    host: \"127.0.0.1\"\n    # db: 0\n",
+		},
+		{
+			name: "不存在的配置段",
+			keys: []string{"database.postgres", "server"},
+			want: testConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testConfig
+			for _, k := range tt.keys {
+				got = removeYAMLKey(got, k)
+			}
+			if got != tt.want {
+				t.Errorf("removeYAMLKey() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveMakeTarget(t *testing.T) {
+	in := ".PHONY: help frontend-dev backend-dev\n\nhelp: ## Show help\n\t@echo help\n\nfrontend-dev: ## Run frontend\n\tcd frontend && npm run dev\n\nbackend-dev: ## Run backend\n\tcd backend && air\n"
+	want := ".PHONY: help backend-dev\n\nhelp: ## Show help\n\t@echo help\n\nbackend-dev: ## Run backend\n\tcd backend && air\n"
+	if got := removeMakeTarget(in, "frontend-dev"); got != want {
+		t.Errorf("removeMakeTarget() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRemoveGoRequire(t *testing.T) {
+	in := "module x\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.11.0\n\tgorm.io/gorm v1.31.1\n)\n\nrequire gorm.io/gen v0.3.27\n"
+	want := "module x\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.11.0\n)\n\n"
+	got := removeGoRequire(removeGoRequire(in, "gorm.io/gorm"), "gorm.io/gen")
+	if got != want {
+		t.Errorf("removeGoRequire() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFeatureSet_StripBlocks(t *testing.T) {
+	m := &Manifest{Features: []Feature{{Name: "mcp"}, {Name: "auth"}, {Name: "mysql"}, {Name: "redis"}}}
+	fs := newFeatureSet(m, &ProjectMeta{Features: []string{"mcp", "redis"}})
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "代码块",
+			in:   "a\n\t// ais:feature mcp\n\tsetupMCP()\n\t// ais:end\n\t// ais:feature auth\n\tuseAuth()\n\t// ais:end\nb",
+			want: "a\n\tsetupMCP()\nb",
+		},
+		{
+			name: "嵌套代码块",
+			in:   "// ais:feature mcp\nx\n// ais:feature auth\ny\n// ais:end\nz\n// ais:end",
+			want: "x\nz",
+		},
+		{
+			name: "单行标记",
+			in:   "\t\"x/database\" // ais:feature mysql|redis\n\t\"x/middleware\" // ais:feature auth\n",
+			want: "\t\"x/database\"\n",
+		},
+		{
+			name: "YAML 注释标记",
+			in:   "# ais:feature mysql\ndsn: x\n# ais:end\nport: 1",
+			want: "port: 1",
+		},
+		{name: "未声明的功能", in: "// ais:feature kafka\n// ais:end", wantErr: true},
+		{name: "缺少 ais:end", in: "// ais:feature mcp\nx", wantErr: true},
+		{name: "多余的 ais:end", in: "x\n// ais:end", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fs.stripBlocks("main.go", []byte(tt.in))
+			if tt.wantErr {
+				if err == nil {
+					t.Error("应返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("stripBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifest_ResolveFeatures(t *testing.T) {
+	m := &Manifest{Features: []Feature{{Name: "mysql"}, {Name: "mcp"}, {Name: "auth", Depends: []string{"mcp"}}}}
+
+	tests := []struct {
+		name     string
+		features []string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "按清单顺序", features: []string{"auth", "mcp"}, want: []string{"mcp", "auth"}},
+		{name: "全部不启用", features: []string{}, want: []string{}},
+		{name: "未知功能", features: []string{"kafka"}, wantErr: true},
+		{name: "依赖未启用", features: []string{"auth"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := &ProjectMeta{Features: tt.features}
+			err := m.ResolveFeatures(meta)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("应返回错误")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(meta.Features, tt.want) {
+				t.Errorf("Features = %v, want %v", meta.Features, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyDir_Features(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		ManifestFile: `
+features:
+  - name: frontend
+    files: ["frontend/**"]
+    makefile:
+      Makefile: ["frontend-dev"]
+  - name: mysql
+    files: ["backend/internal/database/**"]
+    config:
+      backend/config.yaml: ["database"]
+    requires:
+      backend/go.mod: ["gorm.io/gorm"]
+`,
+		"Makefile":                               ".PHONY: frontend-dev backend-dev\n\nfrontend-dev:\n\tnpm run dev\n\nbackend-dev:\n\tair\n",
+		"frontend/package.json":                  "{}",
+		"backend/go.mod":                         "module x\n\nrequire gorm.io/gorm v1.31.1\n",
+		"backend/config.yaml":                    "server:\n  port: 8080\n\ndatabase:\n  host: db\n",
+		"backend/internal/database/database.go":  "package database\n",
+		"backend/internal/http/router/router.go": "package router\n\n// ais:feature mysql\nvar db = 1\n// ais:end\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "demo")
+	meta := &ProjectMeta{Name: "demo", Module: "example.com/demo", Features: []string{}}
+	if err := copyDir(src, dst, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"frontend", "backend/internal/database", ManifestFile} {
+		if _, err := os.Stat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+			t.Errorf("%s 不应生成", name)
+		}
+	}
+
+	want := map[string]string{
+		"Makefile":                               ".PHONY: backend-dev\n\nbackend-dev:\n\tair\n",
+		"backend/go.mod":                         "module x\n\n",
+		"backend/config.yaml":                    "server:\n  port: 8080\n",
+		"backend/internal/http/router/router.go": "package router\n\n",
+	}
+	for name, content := range want {
+		got, err := os.ReadFile(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != content {
+			t.Errorf("%s = %q, want %q", name, got, content)
+		}
+	}
+}
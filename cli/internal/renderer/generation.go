@@ -0,0 +1,214 @@
+package renderer
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+)
+
+// checkTarget 检查目标目录：不存在或为空时直接生成，已存在且不为空时需要 --merge 或 --force，返回是否合并到已有目录
+func checkTarget(meta *ProjectMeta) (bool, error) {
+	entries, err := os.ReadDir(meta.Name)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("无法使用目录 %s: %w", meta.Name, err)
+	}
+	if len(entries) == 0 {
+		return false, nil
+	}
+	if meta.Merge || meta.Force {
+		return true, nil
+	}
+	if meta.DryRun {
+		fmt.Printf("  ⚠️  目录 %s 已存在且不为空，实际生成时需要 --merge 或 --force\n", meta.Name)
+		return false, nil
+	}
+	return false, fmt.Errorf("目录 %s 已存在且不为空，请选择其他项目名称，或使用 --merge 合并（保留已有文件）、--force 覆盖同名文件", meta.Name)
+}
+
+// markConflicts 标记与已有文件同名的计划项：--force 时覆盖，否则保留已有文件
+func markConflicts(ops []FileOp, dst string, force bool) {
+	for i, op := range ops {
+		if op.Action == ActionSkip {
+			continue
+		}
+		if _, err := os.Lstat(filepath.Join(dst, filepath.FromSlash(op.Target))); err != nil {
+			continue
+		}
+		if force {
+			ops[i].Overwrite = true
+		} else {
+			ops[i].Action = ActionSkip
+			ops[i].Reason = "已存在，保留原文件"
+		}
+	}
+}
+
+// generation 一次项目生成：先写入目标目录同级的临时目录，成功后移动到目标位置；失败或中断时回滚
+type generation struct {
+	mu      sync.Mutex
+	dst     string
+	staging string      // 临时目录，files/ 为生成的文件，backup/ 为被覆盖文件的备份
+	moved   []movedFile // 已移入目标目录的文件
+	created []string    // 新建的目录
+	done    bool        // 已提交或已回滚
+}
+
+// movedFile 移入目标目录的文件及其覆盖前的备份
+type movedFile struct {
+	path   string
+	backup string
+}
+
+// run 在临时目录中写入计划并提交
+func (g *generation) run(ops []FileOp) error {
+	abs, err := filepath.Abs(g.dst)
+	if err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(filepath.Dir(abs), "."+filepath.Base(abs)+".ais-*")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	g.mu.Lock()
+	g.staging = staging
+	g.mu.Unlock()
+
+	if err := writePlan(ops, filepath.Join(staging, "files")); err != nil {
+		return err
+	}
+	return g.commit(ops)
+}
+
+// commit 目标目录不存在（或为空）时整体重命名，否则逐个移入文件
+func (g *generation) commit(ops []FileOp) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	files := filepath.Join(g.staging, "files")
+	entries, err := os.ReadDir(g.dst)
+	if os.IsNotExist(err) || err == nil && len(entries) == 0 {
+		empty := err == nil
+		if empty {
+			if err := os.Remove(g.dst); err != nil {
+				return err
+			}
+		}
+		if err := os.Rename(files, g.dst); err != nil {
+			if empty {
+				os.Mkdir(g.dst, 0755)
+			}
+			return fmt.Errorf("移动项目目录失败: %w", err)
+		}
+		g.finish()
+		return nil
+	}
+
+	for _, op := range ops {
+		if op.Action == ActionSkip {
+			continue
+		}
+		rel := filepath.FromSlash(op.Target)
+		dst := filepath.Join(g.dst, rel)
+		if err := g.mkdirs(filepath.Dir(dst)); err != nil {
+			return err
+		}
+
+		m := movedFile{path: dst}
+		if _, err := os.Lstat(dst); err == nil {
+			m.backup = filepath.Join(g.staging, "backup", rel)
+			if err := os.MkdirAll(filepath.Dir(m.backup), 0755); err != nil {
+				return err
+			}
+			if err := os.Rename(dst, m.backup); err != nil {
+				return fmt.Errorf("备份 %s 失败: %w", op.Target, err)
+			}
+		}
+		if err := os.Rename(filepath.Join(files, rel), dst); err != nil {
+			if m.backup != "" {
+				os.Rename(m.backup, dst)
+			}
+			return fmt.Errorf("写入 %s 失败: %w", op.Target, err)
+		}
+		g.moved = append(g.moved, m)
+	}
+	g.finish()
+	return nil
+}
+
+// finish 提交完成，删除临时目录（包括被覆盖文件的备份）
+func (g *generation) finish() {
+	g.done = true
+	os.RemoveAll(g.staging)
+}
+
+// mkdirs 创建目录，并记录最上层新建的目录以便回滚
+func (g *generation) mkdirs(dir string) error {
+	missing := ""
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = d
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if missing == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	g.created = append(g.created, missing)
+	return nil
+}
+
+// rollback 撤销已移入的文件、恢复被覆盖的文件并删除临时目录；已提交时不做任何事
+func (g *generation) rollback() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.done {
+		return
+	}
+	g.done = true
+
+	for i := len(g.moved) - 1; i >= 0; i-- {
+		m := g.moved[i]
+		os.Remove(m.path)
+		if m.backup != "" {
+			os.Rename(m.backup, m.path)
+		}
+	}
+	for i := len(g.created) - 1; i >= 0; i-- {
+		os.RemoveAll(g.created[i])
+	}
+	if g.staging != "" {
+		os.RemoveAll(g.staging)
+	}
+}
+
+// onInterrupt 收到 Ctrl-C 或 SIGTERM 时执行清理并退出，返回的函数用于停止监听
+func onInterrupt(cleanup func()) func() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ch:
+			fmt.Fprintln(os.Stderr, "\n  ⚠️  已中断，正在清理...")
+			cleanup()
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
@@ -0,0 +1,225 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readTree 读取目录下所有文件的内容
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		data, _ := os.ReadFile(path)
+		files[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	return files
+}
+
+// assertNoStaging 检查没有遗留临时目录
+func assertNoStaging(t *testing.T, dir string) {
+	t.Helper()
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".ais-") {
+			t.Errorf("遗留临时目录 %s", e.Name())
+		}
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	root := t.TempDir()
+	os.Mkdir(filepath.Join(root, "empty"), 0755)
+	os.MkdirAll(filepath.Join(root, "full"), 0755)
+	os.WriteFile(filepath.Join(root, "full", "README.md"), []byte("x"), 0644)
+
+	tests := []struct {
+		name      string
+		meta      ProjectMeta
+		wantMerge bool
+		wantErr   bool
+	}{
+		{name: "不存在", meta: ProjectMeta{Name: "missing"}},
+		{name: "空目录", meta: ProjectMeta{Name: "empty"}},
+		{name: "非空目录", meta: ProjectMeta{Name: "full"}, wantErr: true},
+		{name: "非空目录 --merge", meta: ProjectMeta{Name: "full", Merge: true}, wantMerge: true},
+		{name: "非空目录 --force", meta: ProjectMeta{Name: "full", Force: true}, wantMerge: true},
+		{name: "非空目录 --dry-run", meta: ProjectMeta{Name: "full", DryRun: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta := tt.meta
+			meta.Name = filepath.Join(root, meta.Name)
+			merge, err := checkTarget(&meta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if merge != tt.wantMerge {
+				t.Errorf("merge = %v, want %v", merge, tt.wantMerge)
+			}
+		})
+	}
+}
+
+func TestGeneration_Run(t *testing.T) {
+	ops := []FileOp{
+		{Action: ActionCreate, Target: "go.mod", Content: []byte("module shop\n")},
+		{Action: ActionRender, Target: "internal/app/app.go", Content: []byte("package app\n")},
+		{Action: ActionSkip, Source: "cli/", Reason: "忽略规则"},
+	}
+	want := map[string]string{"go.mod": "module shop\n", "internal/app/app.go": "package app\n"}
+
+	tests := []struct {
+		name  string
+		setup func(dst string)
+	}{
+		{name: "目录不存在", setup: func(string) {}},
+		{name: "空目录", setup: func(dst string) { os.Mkdir(dst, 0755) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dst := filepath.Join(root, "shop")
+			tt.setup(dst)
+
+			g := &generation{dst: dst}
+			if err := g.run(ops); err != nil {
+				t.Fatal(err)
+			}
+			g.rollback() // 已提交，不应有任何影响
+
+			got := readTree(t, dst)
+			if len(got) != len(want) {
+				t.Errorf("files = %v, want %v", got, want)
+			}
+			for k, v := range want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+			assertNoStaging(t, root)
+		})
+	}
+}
+
+func TestGeneration_Merge(t *testing.T) {
+	tests := []struct {
+		name  string
+		force bool
+		want  map[string]string
+	}{
+		{
+			name: "--merge 保留已有文件",
+			want: map[string]string{"go.mod": "module old\n", "README.md": "mine\n", "main.go": "package main\n"},
+		},
+		{
+			name:  "--force 覆盖已有文件",
+			force: true,
+			want:  map[string]string{"go.mod": "module shop\n", "README.md": "mine\n", "main.go": "package main\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			dst := filepath.Join(root, "shop")
+			os.Mkdir(dst, 0755)
+			os.WriteFile(filepath.Join(dst, "go.mod"), []byte("module old\n"), 0644)
+			os.WriteFile(filepath.Join(dst, "README.md"), []byte("mine\n"), 0644)
+
+			ops := []FileOp{
+				{Action: ActionCreate, Target: "go.mod", Content: []byte("module shop\n")},
+				{Action: ActionCreate, Target: "main.go", Content: []byte("package main\n")},
+			}
+			markConflicts(ops, dst, tt.force)
+			if tt.force != ops[0].Overwrite || tt.force == (ops[0].Action == ActionSkip) {
+				t.Errorf("go.mod op = %+v", ops[0])
+			}
+
+			g := &generation{dst: dst}
+			if err := g.run(ops); err != nil {
+				t.Fatal(err)
+			}
+			got := readTree(t, dst)
+			if len(got) != len(tt.want) {
+				t.Errorf("files = %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("%s = %q, want %q", k, got[k], v)
+				}
+			}
+			assertNoStaging(t, root)
+		})
+	}
+}
+
+func TestGeneration_Rollback(t *testing.T) {
+	root := t.TempDir()
+	dst := filepath.Join(root, "shop")
+	os.MkdirAll(filepath.Join(dst, "docs"), 0755)
+	os.WriteFile(filepath.Join(dst, "go.mod"), []byte("module old\n"), 0644)
+
+	// 最后一个文件不在临时目录中，模拟移动过程中失败
+	ops := []FileOp{
+		{Action: ActionCreate, Target: "go.mod", Content: []byte("module shop\n"), Overwrite: true},
+		{Action: ActionCreate, Target: "internal/app/app.go", Content: []byte("package app\n")},
+		{Action: ActionCreate, Target: "main.go", Content: []byte("package main\n")},
+	}
+	staging, _ := os.MkdirTemp(root, ".shop.ais-*")
+	g := &generation{dst: dst, staging: staging}
+	if err := writePlan(ops[:2], filepath.Join(staging, "files")); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.commit(ops); err == nil {
+		t.Fatal("缺少临时文件时应返回错误")
+	}
+	g.rollback()
+
+	got := readTree(t, dst)
+	want := map[string]string{"go.mod": "module old\n"}
+	if len(got) != len(want) || got["go.mod"] != want["go.mod"] {
+		t.Errorf("回滚后 files = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "internal")); !os.IsNotExist(err) {
+		t.Error("回滚后应删除新建的目录")
+	}
+	if _, err := os.Stat(filepath.Join(dst, "docs")); err != nil {
+		t.Error("回滚不应删除已有目录")
+	}
+	assertNoStaging(t, root)
+}
+
+func TestRenderProject_Existing(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "go.mod"), []byte("module github.com/richer/ai_skeleton\n"), 0644)
+	os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n"), 0644)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(t.TempDir())
+
+	os.Mkdir("shop", 0755)
+	os.WriteFile(filepath.Join("shop", "main.go"), []byte("// mine\n"), 0644)
+
+	meta := &ProjectMeta{Name: "shop", Module: "example.com/shop", TemplateDir: src}
+	if err := RenderProject(meta); err == nil {
+		t.Fatal("目标目录不为空时应返回错误")
+	}
+
+	meta.Merge = true
+	if err := RenderProject(meta); err != nil {
+		t.Fatal(err)
+	}
+	got := readTree(t, "shop")
+	if got["go.mod"] != "module example.com/shop\n" || got["main.go"] != "// mine\n" {
+		t.Errorf("files = %v", got)
+	}
+	assertNoStaging(t, ".")
+}
@@ -0,0 +1,123 @@
+package renderer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/manifoldco/promptui"
+)
+
+// Hook 模板清单声明的生成后命令，在项目根目录（或 dir）下通过 shell 执行
+type Hook struct {
+	Name     string   `yaml:"name" json:"name"`                   // 显示名称，默认为命令本身
+	Run      string   `yaml:"run" json:"run"`                     // 命令（模板），如 ./scripts/setup.sh {{.Name}}，引用的变量按 shell 规则加引号
+	Dir      string   `yaml:"dir" json:"dir,omitempty"`           // 工作目录，相对项目根目录
+	Features []string `yaml:"features" json:"features,omitempty"` // 只在这些功能全部启用时执行
+}
+
+// validateHooks 校验钩子的命令、工作目录与功能名
+func (m *Manifest) validateHooks() error {
+	declared := make(map[string]bool, len(m.Features))
+	for _, f := range m.Features {
+		declared[f.Name] = true
+	}
+	for i, h := range m.Hooks {
+		if strings.TrimSpace(h.Run) == "" {
+			return fmt.Errorf("%s: 第 %d 个钩子缺少 run", ManifestFile, i+1)
+		}
+		if h.Dir != "" && (path.IsAbs(h.Dir) || path.Clean(h.Dir) == ".." || strings.HasPrefix(path.Clean(h.Dir), "../")) {
+			return fmt.Errorf("%s: 钩子 %s 的 dir 应为项目内的相对路径", ManifestFile, h.label())
+		}
+		for _, f := range h.Features {
+			if !declared[f] {
+				return fmt.Errorf("%s: 钩子 %s 引用未声明的功能 %s", ManifestFile, h.label(), f)
+			}
+		}
+	}
+	return nil
+}
+
+// enabledHooks 返回所需功能均已启用的钩子，并渲染其中的命令（变量值按 shell 规则加引号）
+func (m *Manifest) enabledHooks(meta *ProjectMeta, data map[string]interface{}) ([]Hook, error) {
+	data = shellQuoteData(data)
+	var hooks []Hook
+	for _, h := range m.Hooks {
+		enabled := true
+		for _, f := range h.Features {
+			enabled = enabled && meta.HasFeature(f)
+		}
+		if !enabled {
+			continue
+		}
+
+		run, err := m.execute(h.Run, data)
+		if err != nil {
+			return nil, fmt.Errorf("渲染钩子 %s 失败: %w", h.label(), err)
+		}
+		h.Name = h.label()
+		h.Run = run
+		hooks = append(hooks, h)
+	}
+	return hooks, nil
+}
+
+// ConfirmHooks 确认是否执行钩子：内置与本地模板、--yes 时直接执行，
+// 远程模板（--template-url）列出全部命令并交互式确认
+func ConfirmHooks(meta *ProjectMeta) bool {
+	if len(meta.Hooks) == 0 || meta.TemplateURL == "" || meta.NonInteractive {
+		return true
+	}
+
+	fmt.Printf("模板 %s 声明了以下生成后命令：\n", meta.TemplateURL)
+	for _, h := range meta.Hooks {
+		fmt.Printf("  $ %s%s\n", h.Run, hookDirNote(h))
+	}
+	prompt := promptui.Prompt{Label: "是否执行以上命令", IsConfirm: true}
+	_, err := prompt.Run()
+	return err == nil
+}
+
+// hookDirNote 钩子不在项目根目录执行时的提示
+func hookDirNote(h Hook) string {
+	if h.Dir == "" || path.Clean(h.Dir) == "." {
+		return ""
+	}
+	return "（目录 " + h.Dir + "）"
+}
+
+// shellSafeRe 无需加引号的 shell 参数
+var shellSafeRe = regexp.MustCompile(`^[A-Za-z0-9_./:@+=,-]+$`)
+
+// shellQuoteData 返回渲染命令使用的数据副本，字符串值按 shell 规则加引号，避免项目信息被解释为命令
+func shellQuoteData(data map[string]interface{}) map[string]interface{} {
+	quoted := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if s, ok := v.(string); ok {
+			v = shellQuote(s)
+		}
+		quoted[k] = v
+	}
+	return quoted
+}
+
+// shellQuote 将字符串转为单个 shell 参数（Windows 下为 cmd 的双引号形式）
+func shellQuote(s string) string {
+	if shellSafeRe.MatchString(s) {
+		return s
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// label 钩子的显示名称
+func (h Hook) label() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return h.Run
+}
@@ -0,0 +1,101 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestManifest_ValidateHooks(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{name: "合法", manifest: "features:\n  - name: mysql\nhooks:\n  - run: make gen-sql\n    dir: backend\n    features: [mysql]\n"},
+		{name: "缺少 run", manifest: "hooks:\n  - name: x\n", wantErr: true},
+		{name: "dir 在项目之外", manifest: "hooks:\n  - run: ls\n    dir: ../x\n", wantErr: true},
+		{name: "dir 为绝对路径", manifest: "hooks:\n  - run: ls\n    dir: /tmp\n", wantErr: true},
+		{name: "未声明的功能", manifest: "hooks:\n  - run: ls\n    features: [redis]\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, ManifestFile), []byte(tt.manifest), 0644)
+			if _, err := LoadManifest(dir); (err != nil) != tt.wantErr {
+				t.Errorf("LoadManifest() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewFileRenderer_Hooks(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `features:
+  - name: mysql
+  - name: redis
+hooks:
+  - name: 生成 SQL 代码
+    run: make gen-sql
+    features: [mysql]
+  - run: ./scripts/setup.sh {{.Name}}
+  - run: redis-cli ping
+    features: [redis]
+`
+	os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644)
+
+	meta := &ProjectMeta{Name: "shop", Module: "example.com/shop", Features: []string{"mysql"}}
+	if _, err := newFileRenderer(dir, meta); err != nil {
+		t.Fatal(err)
+	}
+	want := []Hook{
+		{Name: "生成 SQL 代码", Run: "make gen-sql", Features: []string{"mysql"}},
+		{Name: "./scripts/setup.sh {{.Name}}", Run: "./scripts/setup.sh shop"},
+	}
+	if len(meta.Hooks) != len(want) {
+		t.Fatalf("Hooks = %+v, want %+v", meta.Hooks, want)
+	}
+	for i, h := range meta.Hooks {
+		if h.Name != want[i].Name || h.Run != want[i].Run {
+			t.Errorf("Hooks[%d] = %+v, want %+v", i, h, want[i])
+		}
+	}
+}
+
+func TestEnabledHooks_ShellQuote(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("cmd 使用双引号")
+	}
+	m := &Manifest{Hooks: []Hook{{Run: "./scripts/setup.sh {{.Name}} {{.Description}} {{.Owner}}"}}}
+	meta := &ProjectMeta{Name: "shop", Description: "it's $(rm -rf ~); echo", Vars: map[string]string{"Owner": ""}}
+
+	hooks, err := m.enabledHooks(meta, templateData(m, meta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `./scripts/setup.sh shop 'it'\''s $(rm -rf ~); echo' ''`
+	if len(hooks) != 1 || hooks[0].Run != want {
+		t.Errorf("Hooks = %+v, want Run %q", hooks, want)
+	}
+}
+
+func TestConfirmHooks(t *testing.T) {
+	hooks := []Hook{{Run: "make gen-sql"}}
+	tests := []struct {
+		name string
+		meta *ProjectMeta
+	}{
+		{name: "无钩子", meta: &ProjectMeta{TemplateURL: "gh:org/repo"}},
+		{name: "内置模板", meta: &ProjectMeta{Hooks: hooks}},
+		{name: "本地模板", meta: &ProjectMeta{TemplateDir: "./tpl", Hooks: hooks}},
+		{name: "远程模板 --yes", meta: &ProjectMeta{TemplateURL: "gh:org/repo", NonInteractive: true, Hooks: hooks}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !ConfirmHooks(tt.meta) {
+				t.Error("ConfirmHooks() = false, want true")
+			}
+		})
+	}
+}
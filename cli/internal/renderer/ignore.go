@@ -0,0 +1,109 @@
+package renderer
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile 模板根目录中的忽略规则文件，语法同 .gitignore，匹配的文件与目录不会生成
+const IgnoreFile = ".aisignore"
+
+// defaultIgnore 模板没有 .aisignore 时使用的规则
+var defaultIgnore = []string{
+	"/cli/",
+	"/.git/",
+	"/backend/tmp/",
+	"/backend/bin/",
+	"/frontend/node_modules/",
+	"/frontend/dist/",
+	"/requirements/",
+	"/.github/",
+	"/.vscode/",
+	"/.idea/",
+}
+
+// ignoreRule 一条忽略规则
+type ignoreRule struct {
+	pattern  string
+	negate   bool // ! 开头：重新包含之前被忽略的文件
+	dirOnly  bool // / 结尾：只匹配目录
+	anchored bool // 包含 /：相对模板根目录匹配，否则匹配任意层的文件名
+}
+
+// ignoreRules 按顺序匹配，后面的规则优先
+type ignoreRules []ignoreRule
+
+// loadIgnore 读取模板的 .aisignore，不存在时使用默认规则
+func loadIgnore(src string) (ignoreRules, error) {
+	data, err := os.ReadFile(filepath.Join(src, IgnoreFile))
+	if os.IsNotExist(err) {
+		return parseIgnore(defaultIgnore), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return parseIgnore(lines), scanner.Err()
+}
+
+// parseIgnore 解析忽略规则，跳过空行与 # 注释
+func parseIgnore(lines []string) ignoreRules {
+	var rules ignoreRules
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var r ignoreRule
+		if strings.HasPrefix(line, "!") {
+			r.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:] // \# 与 \! 匹配字面量
+		}
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			r.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		r.pattern = line
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// match 判断 / 分隔的相对路径是否被忽略
+func (rules ignoreRules) match(rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if r.anchored {
+			ok = matchSegments(strings.Split(r.pattern, "/"), strings.Split(rel, "/"))
+		} else {
+			ok, _ = path.Match(r.pattern, path.Base(rel))
+		}
+		if ok {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
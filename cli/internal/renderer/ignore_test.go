@@ -0,0 +1,70 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRules_Match(t *testing.T) {
+	rules := parseIgnore([]string{
+		"# 注释",
+		"",
+		"/cli/",
+		"backend/tmp/",
+		"*.log",
+		"!keep.log",
+		"node_modules/",
+		"/docs/**/*.draft.md",
+		`\#notes`,
+	})
+
+	tests := []struct {
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{rel: "cli", isDir: true, want: true},
+		{rel: "cli", isDir: false, want: false},
+		{rel: "client", isDir: true, want: false},
+		{rel: "backend/cli", isDir: true, want: false},
+		{rel: "backend/tmp", isDir: true, want: true},
+		{rel: "frontend/backend/tmp", isDir: true, want: false},
+		{rel: "app.log", want: true},
+		{rel: "backend/logs/app.log", want: true},
+		{rel: "backend/keep.log", want: false},
+		{rel: "frontend/node_modules", isDir: true, want: true},
+		{rel: "docs/a/b/x.draft.md", want: true},
+		{rel: "docs/x.draft.md", want: true},
+		{rel: "docs/x.md", want: false},
+		{rel: "#notes", want: true},
+		{rel: ".gitignore", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rel, func(t *testing.T) {
+			if got := rules.match(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("match(%q, %v) = %v, want %v", tt.rel, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadIgnore(t *testing.T) {
+	src := t.TempDir()
+	rules, err := loadIgnore(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rules.match(".git", true) || rules.match(".gitignore", false) {
+		t.Error("没有 .aisignore 时应使用默认规则，且不应忽略 .gitignore")
+	}
+
+	os.WriteFile(filepath.Join(src, IgnoreFile), []byte("/vendor/\r\n*.tmp\n"), 0644)
+	rules, err = loadIgnore(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rules.match("vendor", true) || !rules.match("a/b.tmp", false) || rules.match("cli", true) {
+		t.Errorf("rules = %+v", rules)
+	}
+}
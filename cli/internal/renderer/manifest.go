@@ -0,0 +1,327 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/manifoldco/promptui"
+	"go.yaml.in/yaml/v3"
+)
+
+// ManifestFile 模板清单文件名（位于模板根目录，不会复制到项目中）
+const ManifestFile = "skeleton.yaml"
+
+// Manifest 模板清单，声明变量、渲染方式与路径重命名规则
+type Manifest struct {
+	Variables []Variable    `yaml:"variables"` // 内置变量之外的模板变量
+	Render    []string      `yaml:"render"`    // 以 text/template 渲染的文件，渲染后去掉 .tmpl 后缀
+	Copy      []string      `yaml:"copy"`      // 原样复制的文件
+	Replace   []ReplaceRule `yaml:"replace"`   // 其余文件按顺序执行的字面量替换
+	Rename    []RenameRule  `yaml:"rename"`    // 路径重命名规则
+	Delims    []string      `yaml:"delims"`    // 模板分隔符，默认 {{ }}
+	Features  []Feature     `yaml:"features"`  // 可选功能
+	Hooks     []Hook        `yaml:"hooks"`     // 生成项目后执行的命令
+}
+
+// Variable 模板变量
+type Variable struct {
+	Name     string `yaml:"name"`
+	Prompt   string `yaml:"prompt"`
+	Default  string `yaml:"default"` // 可引用其他变量，如 {{.Name}}-svc
+	Required bool   `yaml:"required"`
+}
+
+// ReplaceRule 字面量替换规则，To 为模板；Files 为空时作用于所有文件
+type ReplaceRule struct {
+	From  string   `yaml:"from"`
+	To    string   `yaml:"to"`
+	Files []string `yaml:"files"`
+}
+
+// RenameRule 路径重命名规则：将路径中的 From 替换为 To（模板）
+type RenameRule struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// LoadManifest 读取模板根目录下的清单，不存在时返回 nil
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", ManifestFile, err)
+	}
+	if len(m.Delims) != 0 && len(m.Delims) != 2 {
+		return nil, fmt.Errorf("%s: delims 应包含左右两个分隔符", ManifestFile)
+	}
+	for _, v := range m.Variables {
+		if builtinVariables[v.Name] {
+			return nil, fmt.Errorf("%s: 变量 %s 为内置变量，无需声明", ManifestFile, v.Name)
+		}
+	}
+	if err := m.validateFeatures(); err != nil {
+		return nil, err
+	}
+	if err := m.validateHooks(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// builtinVariables 由项目信息提供的内置变量
+var builtinVariables = map[string]bool{
+	"Name": true, "Description": true, "Version": true, "Module": true, "Title": true, "KebabName": true, "Features": true,
+}
+
+// templateData 模板可用的变量，.Features 包含清单声明的全部功能，值为是否启用（如 {{if .Features.frontend}}）
+func templateData(m *Manifest, meta *ProjectMeta) map[string]interface{} {
+	features := make(map[string]bool, len(m.Features))
+	for _, f := range m.Features {
+		features[f.Name] = meta.HasFeature(f.Name)
+	}
+	data := map[string]interface{}{
+		"Name":        meta.Name,
+		"Description": meta.Description,
+		"Version":     meta.Version,
+		"Module":      meta.Module,
+		"Title":       toTitle(meta.Name),
+		"KebabName":   toKebabCase(meta.Name),
+		"Features":    features,
+	}
+	for k, v := range meta.Vars {
+		if !builtinVariables[k] {
+			data[k] = v
+		}
+	}
+	return data
+}
+
+// ResolveVariables 为清单中声明的变量取值：已提供的值 > 默认值 > 交互式输入（必填时，非交互模式下报错）
+func (m *Manifest) ResolveVariables(meta *ProjectMeta) error {
+	if meta.Vars == nil {
+		meta.Vars = make(map[string]string)
+	}
+
+	for _, v := range m.Variables {
+		if _, ok := meta.Vars[v.Name]; ok {
+			continue
+		}
+
+		value, err := m.execute(v.Default, templateData(m, meta))
+		if err != nil {
+			return fmt.Errorf("变量 %s 的默认值无效: %w", v.Name, err)
+		}
+		if value == "" && v.Required {
+			if meta.NonInteractive {
+				return fmt.Errorf("非交互模式下缺少必填变量 %s（可在答案文件的 vars 中提供）", v.Name)
+			}
+			label := v.Prompt
+			if label == "" {
+				label = v.Name
+			}
+			prompt := promptui.Prompt{Label: label}
+			value, err = prompt.Run()
+			if err != nil {
+				return err
+			}
+			value = strings.TrimSpace(value)
+			if value == "" {
+				return fmt.Errorf("变量 %s 为必填项", v.Name)
+			}
+		}
+		meta.Vars[v.Name] = value
+	}
+	return nil
+}
+
+// execute 以清单的分隔符渲染模板字符串
+func (m *Manifest) execute(text string, data map[string]interface{}) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := m.parse("", text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// parse 解析模板，引用未定义的变量时报错
+func (m *Manifest) parse(name, text string) (*template.Template, error) {
+	tmpl := template.New(name).Option("missingkey=error")
+	if len(m.Delims) == 2 {
+		tmpl = tmpl.Delims(m.Delims[0], m.Delims[1])
+	}
+	return tmpl.Parse(text)
+}
+
+// fileRenderer 决定模板文件在项目中的路径与内容
+type fileRenderer interface {
+	// Skip 跳过该文件或目录的原因，不跳过时返回空字符串
+	Skip(rel string) string
+	// Path 目标相对路径（使用 / 分隔）
+	Path(rel string) (string, error)
+	// Render 处理文件内容
+	Render(rel string, content []byte) ([]byte, error)
+}
+
+// newFileRenderer 模板包含清单时按清单渲染，否则沿用占位符替换
+func newFileRenderer(templateDir string, meta *ProjectMeta) (fileRenderer, error) {
+	m, err := LoadManifest(templateDir)
+	if err != nil || m == nil {
+		return legacyRenderer{meta: meta}, err
+	}
+	if err := m.ResolveFeatures(meta); err != nil {
+		return nil, err
+	}
+	if err := m.ResolveVariables(meta); err != nil {
+		return nil, err
+	}
+	data := templateData(m, meta)
+	if meta.Hooks, err = m.enabledHooks(meta, data); err != nil {
+		return nil, err
+	}
+	return &manifestRenderer{manifest: m, features: newFeatureSet(m, meta), data: data}, nil
+}
+
+// legacyRenderer 未提供清单的模板：替换 ai_skeleton 等占位符
+type legacyRenderer struct {
+	meta *ProjectMeta
+}
+
+func (r legacyRenderer) Skip(rel string) string {
+	return ""
+}
+
+func (r legacyRenderer) Path(rel string) (string, error) {
+	return rel, nil
+}
+
+func (r legacyRenderer) Render(rel string, content []byte) ([]byte, error) {
+	return []byte(replaceContent(string(content), r.meta)), nil
+}
+
+// manifestRenderer 按清单渲染
+type manifestRenderer struct {
+	manifest *Manifest
+	features featureSet
+	data     map[string]interface{}
+}
+
+func (r *manifestRenderer) Skip(rel string) string {
+	if name := r.features.skip(rel); name != "" {
+		return "未启用功能 " + name
+	}
+	return ""
+}
+
+func (r *manifestRenderer) Path(rel string) (string, error) {
+	if matchAny(r.manifest.Render, rel) {
+		rel = strings.TrimSuffix(rel, ".tmpl")
+	}
+	for _, rule := range r.manifest.Rename {
+		if !strings.Contains(rel, rule.From) {
+			continue
+		}
+		to, err := r.manifest.execute(rule.To, r.data)
+		if err != nil {
+			return "", fmt.Errorf("重命名规则 %s 无效: %w", rule.From, err)
+		}
+		rel = strings.ReplaceAll(rel, rule.From, to)
+	}
+	return rel, nil
+}
+
+func (r *manifestRenderer) Render(rel string, content []byte) ([]byte, error) {
+	if matchAny(r.manifest.Copy, rel) {
+		return content, nil
+	}
+	content, err := r.features.apply(rel, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if matchAny(r.manifest.Render, rel) {
+		tmpl, err := r.manifest.parse(rel, string(content))
+		if err != nil {
+			return nil, fmt.Errorf("解析模板 %s 失败: %w", rel, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, r.data); err != nil {
+			return nil, fmt.Errorf("渲染模板 %s 失败: %w", rel, err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	for _, rule := range r.manifest.Replace {
+		if len(rule.Files) > 0 && !matchAny(rule.Files, rel) {
+			continue
+		}
+		if !bytes.Contains(content, []byte(rule.From)) {
+			continue
+		}
+		to, err := r.manifest.execute(rule.To, r.data)
+		if err != nil {
+			return nil, fmt.Errorf("替换规则 %s 无效: %w", rule.From, err)
+		}
+		content = bytes.ReplaceAll(content, []byte(rule.From), []byte(to))
+	}
+	return content, nil
+}
+
+// matchAny 路径是否匹配任一模式
+func matchAny(patterns []string, rel string) bool {
+	for _, p := range patterns {
+		if matchGlob(p, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob 匹配 / 分隔的相对路径：** 匹配任意层目录，不含 / 的模式只匹配文件名
+func matchGlob(pattern, rel string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(rel))
+		return ok
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(rel, "/"))
+}
+
+// matchSegments 逐段匹配
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
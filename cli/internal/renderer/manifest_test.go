@@ -0,0 +1,153 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.png", "logo.png", true},
+		{"*.png", "assets/img/logo.png", true},
+		{"**/*.tmpl", "README.md.tmpl", true},
+		{"**/*.tmpl", "backend/config/app.yaml.tmpl", true},
+		{"**/*.tmpl", "backend/app.yaml", false},
+		{"backend/config.yaml", "backend/config.yaml", true},
+		{"backend/config.yaml", "frontend/backend/config.yaml", false},
+		{"frontend/public/**", "frontend/public/fonts/a.woff", true},
+		{"frontend/public/**", "frontend/src/a.ts", false},
+		{"backend/*/main.go", "backend/cmd/main.go", true},
+		{"backend/*/main.go", "backend/cmd/server/main.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+const testManifest = `
+variables:
+  - name: Port
+    default: "8080"
+  - name: Service
+    default: "{{.Name}}-svc"
+render: ["**/*.tmpl"]
+copy: ["frontend/package-lock.json"]
+replace:
+  - from: "github.com/richer/ai_skeleton"
+    to: "{{.Module}}"
+  - from: "ai_skeleton"
+    to: "{{.Name}}"
+  - from: '"version": "1.0.0"'
+    to: '"version": "{{.Version}}"'
+    files: ["frontend/package.json"]
+rename:
+  - from: "cmd/skeleton"
+    to: "cmd/{{.Name}}"
+`
+
+func TestManifestRenderer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(testManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	meta := &ProjectMeta{Name: "shop", Version: "2.0.0", Module: "example.com/shop", Vars: map[string]string{"Port": "9090"}}
+	r, err := newFileRenderer(dir, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Vars["Service"] != "shop-svc" || meta.Vars["Port"] != "9090" {
+		t.Fatalf("变量取值错误: %v", meta.Vars)
+	}
+
+	paths := []struct{ rel, want string }{
+		{"backend/config.yaml.tmpl", "backend/config.yaml"},
+		{"backend/cmd/skeleton/main.go", "backend/cmd/shop/main.go"},
+		{"README.md", "README.md"},
+	}
+	for _, p := range paths {
+		got, err := r.Path(p.rel)
+		if err != nil || got != p.want {
+			t.Errorf("Path(%s) = %s, %v, want %s", p.rel, got, err, p.want)
+		}
+	}
+
+	contents := []struct{ rel, in, want string }{
+		{"backend/config.yaml.tmpl", "name: {{.Name}}\nport: {{.Port}}\n", "name: shop\nport: 9090\n"},
+		{"backend/main.go", `import "github.com/richer/ai_skeleton/internal"`, `import "example.com/shop/internal"`},
+		{"frontend/package.json", `{"name": "x", "version": "1.0.0"}`, `{"name": "x", "version": "2.0.0"}`},
+		{"frontend/other.json", `{"name": "x", "version": "1.0.0"}`, `{"name": "x", "version": "1.0.0"}`},
+		{"frontend/package-lock.json", `{"name": "ai_skeleton", "version": "1.0.0"}`, `{"name": "ai_skeleton", "version": "1.0.0"}`},
+	}
+	for _, c := range contents {
+		got, err := r.Render(c.rel, []byte(c.in))
+		if err != nil || string(got) != c.want {
+			t.Errorf("Render(%s) = %q, %v, want %q", c.rel, got, err, c.want)
+		}
+	}
+
+	if _, err := r.Render("bad.tmpl", []byte("{{.Missing}}")); err == nil {
+		t.Error("引用未定义变量时应报错")
+	}
+}
+
+func TestManifestRenderer_DisabledFeature(t *testing.T) {
+	dir := t.TempDir()
+	manifest := "render: [\"**/*.tmpl\"]\nfeatures:\n  - name: frontend\n  - name: mcp\n"
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := newFileRenderer(dir, &ProjectMeta{Name: "shop", Features: []string{"mcp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := "{{if .Features.frontend}}frontend{{end}}{{if .Features.mcp}}mcp{{end}}"
+	got, err := r.Render("README.md.tmpl", []byte(in))
+	if err != nil || string(got) != "mcp" {
+		t.Errorf("Render() = %q, %v, want %q", got, err, "mcp")
+	}
+}
+
+func TestNewFileRenderer_Legacy(t *testing.T) {
+	r, err := newFileRenderer(t.TempDir(), &ProjectMeta{Name: "shop", Module: "example.com/shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.(legacyRenderer); !ok {
+		t.Fatalf("无清单时应使用占位符替换，got %T", r)
+	}
+	got, _ := r.Render("main.go", []byte(`"github.com/richer/ai_skeleton/internal"`))
+	if string(got) != `"example.com/shop/internal"` {
+		t.Errorf("Render() = %s", got)
+	}
+}
+
+func TestLoadManifest_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{"声明内置变量", "variables:\n  - name: Name\n"},
+		{"分隔符数量错误", "delims: ['[[']\n"},
+		{"YAML 格式错误", "render: [\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, ManifestFile), []byte(tt.manifest), 0644)
+			if _, err := LoadManifest(dir); err == nil {
+				t.Error("应返回错误")
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 文件的处理方式
+const (
+	ActionCreate = "create" // 原样复制
+	ActionRender = "render" // 内容经过占位符替换或模板渲染
+	ActionSkip   = "skip"   // 不生成
+)
+
+// FileOp 模板中一个文件（或被跳过的目录）的生成计划
+type FileOp struct {
+	Action    string
+	Source    string      // 模板中的相对路径（/ 分隔，目录以 / 结尾）
+	Target    string      // 项目中的相对路径
+	Reason    string      // 跳过原因
+	Overwrite bool        // 覆盖目标目录中的已有文件（--force）
+	Mode      os.FileMode // 文件权限，保留模板中的可执行位
+	Link      string      // 符号链接的目标，非空时不写入内容
+	Binary    bool        // 二进制文件，原样复制
+	Before    []byte      // 模板中的内容
+	Content   []byte      // 生成的内容
+}
+
+// planDir 遍历模板目录，计算每个文件的目标路径与内容，不写入任何文件
+func planDir(src string, meta *ProjectMeta) ([]FileOp, error) {
+	r, err := newFileRenderer(src, meta)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := loadIgnore(src)
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", IgnoreFile, err)
+	}
+
+	var ops []FileOp
+	err = filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, _ := filepath.Rel(src, path)
+		if relPath == "." {
+			return nil
+		}
+		rel := filepath.ToSlash(relPath)
+
+		// 跳过 .aisignore 匹配的文件、模板清单与未启用功能的文件
+		reason := ""
+		switch {
+		case rel == IgnoreFile || ignore.match(rel, info.IsDir()):
+			reason = "忽略规则"
+		case rel == ManifestFile:
+			reason = "模板清单"
+		case info.Mode()&os.ModeSymlink != 0:
+			reason = r.Skip(rel)
+			if reason == "" {
+				reason = checkLink(src, path)
+			}
+		case !info.IsDir() && !info.Mode().IsRegular():
+			reason = "不是普通文件"
+		default:
+			reason = r.Skip(rel)
+		}
+		if reason != "" {
+			if info.IsDir() {
+				ops = append(ops, FileOp{Action: ActionSkip, Source: rel + "/", Reason: reason})
+				return filepath.SkipDir
+			}
+			ops = append(ops, FileOp{Action: ActionSkip, Source: rel, Reason: reason})
+			return nil
+		}
+
+		// 目录在写入文件时按需创建，避免留下空目录
+		if info.IsDir() {
+			return nil
+		}
+
+		target, err := r.Path(rel)
+		if err != nil {
+			return err
+		}
+
+		// 符号链接原样保留
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, FileOp{Action: ActionCreate, Source: rel, Target: target, Link: filepath.ToSlash(link)})
+			return nil
+		}
+
+		before, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		// 二进制文件（图片、字体等）不做替换
+		op := FileOp{Action: ActionCreate, Source: rel, Target: target, Mode: info.Mode().Perm(), Before: before, Content: before}
+		if isBinary(before) {
+			op.Binary = true
+		} else if op.Content, err = r.Render(rel, before); err != nil {
+			return err
+		}
+		if target != rel || !bytes.Equal(before, op.Content) {
+			op.Action = ActionRender
+		}
+		ops = append(ops, op)
+		return nil
+	})
+	return ops, err
+}
+
+// checkLink 检查符号链接，指向模板目录之外时返回跳过原因
+func checkLink(src, path string) string {
+	link, err := os.Readlink(path)
+	if err != nil {
+		return "无法读取链接"
+	}
+	if filepath.IsAbs(link) {
+		return "链接指向模板之外"
+	}
+	rel, err := filepath.Rel(src, filepath.Join(filepath.Dir(path), link))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "链接指向模板之外"
+	}
+	return ""
+}
+
+// binarySniffLen 判断二进制文件时检查的前缀长度
+const binarySniffLen = 8000
+
+// isBinary 前 8000 字节中包含 NUL 时视为二进制文件（与 git 的判断方式相同）
+func isBinary(data []byte) bool {
+	if len(data) > binarySniffLen {
+		data = data[:binarySniffLen]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// writePlan 按计划在 dst 下写入文件，保留文件权限与符号链接
+func writePlan(ops []FileOp, dst string) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if op.Action == ActionSkip {
+			continue
+		}
+		path := filepath.Join(dst, filepath.FromSlash(op.Target))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if op.Link != "" {
+			if err := os.Symlink(filepath.FromSlash(op.Link), path); err != nil {
+				return err
+			}
+			continue
+		}
+		mode := op.Mode
+		if mode == 0 {
+			mode = 0644
+		}
+		if err := os.WriteFile(path, op.Content, mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrintPlan 输出生成计划与将执行的钩子，并以统一 diff 格式列出渲染后发生变化的行
+func PrintPlan(w io.Writer, ops []FileOp, hooks []Hook) {
+	var created, rendered, skipped int
+	for _, op := range ops {
+		switch op.Action {
+		case ActionCreate:
+			created++
+			switch {
+			case op.Link != "":
+				fmt.Fprintf(w, "  + link    %s -> %s%s\n", op.Target, op.Link, overwriteNote(op))
+			case op.Binary:
+				fmt.Fprintf(w, "  + create  %s（二进制）%s\n", op.Target, overwriteNote(op))
+			default:
+				fmt.Fprintf(w, "  + create  %s%s\n", op.Target, overwriteNote(op))
+			}
+		case ActionRender:
+			rendered++
+			if op.Target != op.Source {
+				fmt.Fprintf(w, "  ~ render  %s → %s%s\n", op.Source, op.Target, overwriteNote(op))
+			} else {
+				fmt.Fprintf(w, "  ~ render  %s%s\n", op.Target, overwriteNote(op))
+			}
+		case ActionSkip:
+			skipped++
+			fmt.Fprintf(w, "  - skip    %s（%s）\n", op.Source, op.Reason)
+		}
+	}
+	fmt.Fprintf(w, "\n共 %d 个文件：创建 %d，渲染 %d，跳过 %d\n", created+rendered, created, rendered, skipped)
+
+	if len(hooks) > 0 {
+		fmt.Fprintf(w, "\n生成后将执行 %d 个钩子（--skiphooks 跳过）：\n", len(hooks))
+		for _, h := range hooks {
+			fmt.Fprintf(w, "  $ %s%s\n", h.Run, hookDirNote(h))
+		}
+	}
+
+	for _, op := range ops {
+		if op.Action != ActionRender || bytes.Equal(op.Before, op.Content) {
+			continue
+		}
+		fmt.Fprintln(w)
+		fmt.Fprint(w, unifiedDiff("a/"+op.Source, "b/"+op.Target, string(op.Before), string(op.Content)))
+	}
+}
+
+// overwriteNote 覆盖已有文件时的提示
+func overwriteNote(op FileOp) string {
+	if op.Overwrite {
+		return "（覆盖已有文件）"
+	}
+	return ""
+}
+
+// diffContext 统一 diff 中变化行前后保留的上下文行数
+const diffContext = 3
+
+// maxDiffCells 逐行比较的规模上限，超过时整段显示为删除后新增
+const maxDiffCells = 4 << 20
+
+// unifiedDiff 生成统一 diff，内容相同时返回空字符串
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for i := 0; i < len(edits); {
+		// 找到下一处变化，连同上下文组成一个 hunk
+		for i < len(edits) && edits[i].op == ' ' {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			// 两处变化之间的相同行不超过 2 倍上下文时合并为一个 hunk
+			next := end
+			for next < len(edits) && edits[next].op == ' ' {
+				next++
+			}
+			if next == len(edits) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+		}
+		stop := end + diffContext
+		if stop > len(edits) {
+			stop = len(edits)
+		}
+		writeHunk(&b, edits[start:stop])
+		i = stop
+	}
+	return b.String()
+}
+
+// writeHunk 输出一个 hunk
+func writeHunk(b *strings.Builder, edits []lineEdit) {
+	fromStart, toStart := edits[0].from, edits[0].to
+	var fromLen, toLen int
+	for _, e := range edits {
+		if e.op != '+' {
+			fromLen++
+		}
+		if e.op != '-' {
+			toLen++
+		}
+	}
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(fromStart, fromLen), hunkRange(toStart, toLen))
+	for _, e := range edits {
+		b.WriteByte(e.op)
+		b.WriteString(e.text)
+		if !strings.HasSuffix(e.text, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange hunk 头中的行范围（行号从 1 开始，空范围使用前一行）
+func hunkRange(start, n int) string {
+	if n == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+// lineEdit diff 中的一行：op 为 ' '、'-' 或 '+'，from/to 为该行之前两侧已有的行数
+type lineEdit struct {
+	op       byte
+	text     string
+	from, to int
+}
+
+// diffLines 基于最长公共子序列的逐行比较
+func diffLines(a, b []string) []lineEdit {
+	// 去掉相同的前缀与后缀，缩小比较范围
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+
+	edits := make([]lineEdit, 0, len(a)+len(b))
+	i, j := 0, 0
+	emit := func(op byte, text string) {
+		edits = append(edits, lineEdit{op: op, text: text, from: i, to: j})
+		if op != '+' {
+			i++
+		}
+		if op != '-' {
+			j++
+		}
+	}
+
+	for _, line := range a[:pre] {
+		emit(' ', line)
+	}
+	if len(ma)*len(mb) > maxDiffCells {
+		for _, line := range ma {
+			emit('-', line)
+		}
+		for _, line := range mb {
+			emit('+', line)
+		}
+	} else {
+		// lcs[x][y] 为 ma[x:] 与 mb[y:] 的最长公共子序列长度
+		lcs := make([][]int, len(ma)+1)
+		for x := range lcs {
+			lcs[x] = make([]int, len(mb)+1)
+		}
+		for x := len(ma) - 1; x >= 0; x-- {
+			for y := len(mb) - 1; y >= 0; y-- {
+				if ma[x] == mb[y] {
+					lcs[x][y] = lcs[x+1][y+1] + 1
+				} else if lcs[x+1][y] >= lcs[x][y+1] {
+					lcs[x][y] = lcs[x+1][y]
+				} else {
+					lcs[x][y] = lcs[x][y+1]
+				}
+			}
+		}
+		x, y := 0, 0
+		for x < len(ma) || y < len(mb) {
+			switch {
+			case x < len(ma) && y < len(mb) && ma[x] == mb[y]:
+				emit(' ', ma[x])
+				x++
+				y++
+			case y == len(mb) || x < len(ma) && lcs[x+1][y] >= lcs[x][y+1]:
+				emit('-', ma[x])
+				x++
+			default:
+				emit('+', mb[y])
+				y++
+			}
+		}
+	}
+	for _, line := range a[len(a)-suf:] {
+		emit(' ', line)
+	}
+	return edits
+}
+
+// splitLines 按行切分，保留换行符
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,179 @@
+package renderer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		want     string
+	}{
+		{name: "内容相同", from: "a\nb\n", to: "a\nb\n", want: ""},
+		{
+			name: "替换一行",
+			from: "module github.com/richer/ai_skeleton\n\ngo 1.23\n",
+			to:   "module example.com/shop\n\ngo 1.23\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -1,3 +1,3 @@\n-module github.com/richer/ai_skeleton\n+module example.com/shop\n \n go 1.23\n",
+		},
+		{
+			name: "相距较远的变化分为两个 hunk",
+			from: "a\n1\n2\n3\n4\n5\n6\n7\n8\nb\n",
+			to:   "A\n1\n2\n3\n4\n5\n6\n7\n8\nB\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -7,4 +7,4 @@\n 6\n 7\n 8\n-b\n+B\n",
+		},
+		{
+			name: "删除与新增",
+			from: "a\nb\nc\n",
+			to:   "a\nc\nd\n",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -1,3 +1,3 @@\n a\n-b\n c\n+d\n",
+		},
+		{
+			name: "末尾没有换行",
+			from: "a",
+			to:   "b",
+			want: "--- a/go.mod\n+++ b/go.mod\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a/go.mod", "b/go.mod", tt.from, tt.to); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanDir(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		ManifestFile:          "render: [\"**/*.tmpl\"]\nreplace:\n  - from: ai_skeleton\n    to: \"{{.Name}}\"\n",
+		"README.md.tmpl":      "# {{.Name}}\n",
+		"backend/go.mod":      "module ai_skeleton\n",
+		"backend/go.sum":      "sum\n",
+		"cli/main.go":         "package main\n",
+		"frontend/dist/a.js":  "x",
+		"frontend/index.html": "<html></html>\n",
+		".gitignore":          "bin/\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	ops, err := planDir(src, &ProjectMeta{Name: "shop", Module: "example.com/shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]FileOp, len(ops))
+	for _, op := range ops {
+		got[op.Source] = op
+	}
+	want := []struct{ source, action, target, reason string }{
+		{ManifestFile, ActionSkip, "", "模板清单"},
+		{"README.md.tmpl", ActionRender, "README.md", ""},
+		{"backend/go.mod", ActionRender, "backend/go.mod", ""},
+		{"backend/go.sum", ActionCreate, "backend/go.sum", ""},
+		{"cli/", ActionSkip, "", "忽略规则"},
+		{"frontend/dist/", ActionSkip, "", "忽略规则"},
+		{"frontend/index.html", ActionCreate, "frontend/index.html", ""},
+		{".gitignore", ActionCreate, ".gitignore", ""},
+	}
+	if len(ops) != len(want) {
+		t.Errorf("planDir() 返回 %d 项，want %d", len(ops), len(want))
+	}
+	for _, w := range want {
+		op, ok := got[w.source]
+		if !ok {
+			t.Errorf("缺少 %s", w.source)
+			continue
+		}
+		if op.Action != w.action || op.Target != w.target || op.Reason != w.reason {
+			t.Errorf("%s = %+v, want action=%s target=%s reason=%s", w.source, op, w.action, w.target, w.reason)
+		}
+	}
+
+	var out bytes.Buffer
+	PrintPlan(&out, ops, []Hook{{Name: "生成 SQL 代码", Run: "make gen-sql", Dir: "backend"}})
+	for _, s := range []string{"~ render  README.md.tmpl → README.md", "- skip    cli/（忽略规则）", "-module ai_skeleton\n+module shop\n", "共 5 个文件：创建 3，渲染 2，跳过 3", "$ make gen-sql（目录 backend）"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("PrintPlan() 输出缺少 %q:\n%s", s, out.String())
+		}
+	}
+}
+
+func TestPlanDir_FileTypes(t *testing.T) {
+	src := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR ai_skeleton")
+	os.WriteFile(filepath.Join(src, "install.sh"), []byte("#!/bin/sh\necho ai_skeleton\n"), 0755)
+	os.WriteFile(filepath.Join(src, "logo.png"), png, 0644)
+	os.WriteFile(filepath.Join(src, "notes.log"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(src, IgnoreFile), []byte("*.log\n"), 0644)
+	os.Symlink("install.sh", filepath.Join(src, "setup.sh"))
+	os.Symlink("../outside", filepath.Join(src, "escape"))
+
+	ops, err := planDir(src, &ProjectMeta{Name: "shop", Module: "example.com/shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]FileOp, len(ops))
+	for _, op := range ops {
+		got[op.Source] = op
+	}
+	if op := got["install.sh"]; op.Action != ActionRender || op.Mode != 0755 {
+		t.Errorf("install.sh = %+v", op)
+	}
+	if op := got["logo.png"]; op.Action != ActionCreate || !op.Binary || !bytes.Equal(op.Content, png) {
+		t.Errorf("二进制文件不应替换内容: %+v", op)
+	}
+	if op := got["setup.sh"]; op.Action != ActionCreate || op.Link != "install.sh" {
+		t.Errorf("setup.sh = %+v", op)
+	}
+	if op := got["escape"]; op.Action != ActionSkip || op.Reason != "链接指向模板之外" {
+		t.Errorf("escape = %+v", op)
+	}
+	for _, name := range []string{"notes.log", IgnoreFile} {
+		if op := got[name]; op.Action != ActionSkip || op.Reason != "忽略规则" {
+			t.Errorf("%s = %+v", name, op)
+		}
+	}
+
+	dst := filepath.Join(t.TempDir(), "shop")
+	if err := writePlan(ops, dst); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "install.sh")); err != nil || info.Mode().Perm()&0100 == 0 {
+		t.Errorf("install.sh 应保留可执行权限: %v", info.Mode())
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "setup.sh")); err != nil || link != "install.sh" {
+		t.Errorf("setup.sh 应为符号链接: %q, %v", link, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "logo.png")); !bytes.Equal(data, png) {
+		t.Error("logo.png 内容被修改")
+	}
+}
+
+func TestRenderProject_DryRun(t *testing.T) {
+	src := t.TempDir()
+	os.WriteFile(filepath.Join(src, "go.mod"), []byte("module github.com/richer/ai_skeleton\n"), 0644)
+
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(t.TempDir())
+
+	meta := &ProjectMeta{Name: "shop", Module: "example.com/shop", TemplateDir: src, DryRun: true}
+	if err := RenderProject(meta); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat("shop"); !os.IsNotExist(err) {
+		t.Error("--dry-run 不应写入文件")
+	}
+}
@@ -3,10 +3,11 @@ package renderer
 import (
 	"archive/zip"
 	"fmt"
+	"go/token"
 	"io"
-	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/manifoldco/promptui"
@@ -17,20 +18,86 @@ const DefaultTemplateURL = "https://github.com/richer421/ai_skeleton/archive/mai
 
 // ProjectMeta 项目元信息
 type ProjectMeta struct {
-	Name         string // 项目名称
-	Description  string // 项目描述
-	Version      string // 项目版本
-	Module       string // Go 模块路径
-	TemplateURL  string // 自定义模板仓库地址（可选，用于私有仓库）
+	Name           string            `json:"name"`                      // 项目名称
+	Description    string            `json:"description"`               // 项目描述
+	Version        string            `json:"version"`                   // 项目版本
+	Module         string            `json:"module"`                    // Go 模块路径
+	TemplateURL    string            `json:"template_url,omitempty"`    // 自定义模板仓库地址（可选，用于私有仓库）
+	TemplateDir    string            `json:"template_dir,omitempty"`    // 本地模板目录（可选，离线使用）
+	TemplateZip    string            `json:"template_zip,omitempty"`    // 本地模板 ZIP（可选，离线使用）
+	Offline        bool              `json:"offline,omitempty"`         // 使用 CLI 内嵌的模板快照
+	TemplateRef    string            `json:"template_ref,omitempty"`    // 模板版本（tag、分支或 commit），用于远程模板
+	TemplateSHA256 string            `json:"template_sha256,omitempty"` // 模板归档的 SHA-256（可选），用于远程模板与本地 ZIP
+	NoCache        bool              `json:"no_cache,omitempty"`        // 不使用模板缓存
+	Download       DownloadOptions   `json:"-"`                         // 下载远程模板的认证、代理、超时与重试
+	Vars           map[string]string `json:"vars,omitempty"`            // 模板清单中声明的其他变量
+	Features       []string          `json:"features"`                  // 启用的可选功能，nil 表示交互式选择（非交互模式下使用默认值）
+	NonInteractive bool              `json:"-"`                         // 非交互模式：缺少必填项时直接报错
+	DryRun         bool              `json:"-"`                         // 只输出生成计划与 diff，不写入文件
+	Merge          bool              `json:"-"`                         // 合并到已有目录，保留同名文件
+	Force          bool              `json:"-"`                         // 合并到已有目录，覆盖同名文件
+	Hooks          []Hook            `json:"hooks,omitempty"`           // 模板清单声明的生成后命令，由 RenderProject 填充
 }
 
-// PromptProjectInfo 交互式收集项目信息
+// nameRe 项目名称：字母开头，只包含字母、数字与下划线
+var nameRe = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// modulePathElemRe Go 模块路径中的一段
+var modulePathElemRe = regexp.MustCompile(`^[A-Za-z0-9_~-][A-Za-z0-9._~-]*$`)
+
+// ValidateName 校验项目名称：需为合法的 Go 标识符，同时用作目录名
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("项目名称不能为空")
+	}
+	if !nameRe.MatchString(name) || !token.IsIdentifier(name) {
+		return fmt.Errorf("项目名称 %q 无效：需以字母开头，只包含字母、数字和下划线（如 my_project）", name)
+	}
+	return nil
+}
+
+// ValidateModule 校验 Go 模块路径（如 github.com/user/my_project）
+func ValidateModule(path string) error {
+	if path == "" {
+		return fmt.Errorf("Go 模块路径不能为空")
+	}
+	for _, elem := range strings.Split(path, "/") {
+		if !modulePathElemRe.MatchString(elem) || strings.HasSuffix(elem, ".") {
+			return fmt.Errorf("Go 模块路径 %q 无效：以 / 分隔的每一段只能包含字母、数字和 . _ ~ -", path)
+		}
+	}
+	return nil
+}
+
+// Validate 校验项目名称与模块路径
+func (meta *ProjectMeta) Validate() error {
+	if err := ValidateName(meta.Name); err != nil {
+		return err
+	}
+	return ValidateModule(meta.Module)
+}
+
+// PromptProjectInfo 交互式收集项目信息；非交互模式下缺少项目名称时报错，其余使用默认值
 func PromptProjectInfo(meta *ProjectMeta) error {
+	if meta.NonInteractive {
+		if meta.Name == "" {
+			return fmt.Errorf("非交互模式下必须指定项目名称（参数、--name 或答案文件中的 name）")
+		}
+		if meta.Version == "" {
+			meta.Version = "1.0.0"
+		}
+		if meta.Module == "" {
+			meta.Module = fmt.Sprintf("github.com/user/%s", meta.Name)
+		}
+		return meta.Validate()
+	}
+
 	// 项目名称
 	if meta.Name == "" {
 		prompt := promptui.Prompt{
-			Label:   "项目名称",
-			Default: filepath.Base(getCurrentDir()),
+			Label:    "项目名称",
+			Default:  filepath.Base(getCurrentDir()),
+			Validate: ValidateName,
 		}
 		name, err := prompt.Run()
 		if err != nil {
@@ -61,8 +128,9 @@ func PromptProjectInfo(meta *ProjectMeta) error {
 	if meta.Module == "" {
 		defaultModule := fmt.Sprintf("github.com/user/%s", meta.Name)
 		prompt := promptui.Prompt{
-			Label:   "Go 模块路径",
-			Default: defaultModule,
+			Label:    "Go 模块路径",
+			Default:  defaultModule,
+			Validate: ValidateModule,
 		}
 		module, err := prompt.Run()
 		if err != nil {
@@ -71,97 +139,64 @@ func PromptProjectInfo(meta *ProjectMeta) error {
 		meta.Module = strings.TrimSpace(module)
 	}
 
-	return nil
+	return meta.Validate()
 }
 
 // RenderProject 渲染项目文件
 func RenderProject(meta *ProjectMeta) error {
 	// 检查目标目录是否存在
-	if _, err := os.Stat(meta.Name); err == nil {
-		return fmt.Errorf("目录 %s 已存在，请选择其他项目名称", meta.Name)
-	}
-
-	// 确定模板URL
-	templateURL := DefaultTemplateURL
-	if meta.TemplateURL != "" {
-		templateURL = meta.TemplateURL
-		fmt.Println("  🌐 正在从私有仓库获取模板...")
-	} else {
-		fmt.Println("  🌐 正在从官方仓库获取最新模板...")
-	}
-
-	// 从远程下载模板
-	if err := downloadAndExtractTemplate(meta.Name, templateURL, meta); err != nil {
-		return fmt.Errorf("下载模板失败: %w", err)
-	}
-
-	fmt.Println("  ✓ 项目文件生成完成")
-	return nil
-}
-
-// downloadAndExtractTemplate 从远程下载并提取模板
-func downloadAndExtractTemplate(dst, templateURL string, meta *ProjectMeta) error {
-	// 创建临时目录
-	tempDir, err := os.MkdirTemp("", "ai_skeleton_template_*")
+	merge, err := checkTarget(meta)
 	if err != nil {
 		return err
 	}
-	defer os.RemoveAll(tempDir)
-
-	// 下载模板
-	zipPath := filepath.Join(tempDir, "template.zip")
-	if err := downloadFile(templateURL, zipPath); err != nil {
-		return err
-	}
-
-	// 解压模板
-	extractPath := filepath.Join(tempDir, "extracted")
-	if err := unzip(zipPath, extractPath); err != nil {
-		return err
-	}
 
-	// 查找实际的模板目录（通常是 ai_skeleton-main）
-	templateDir := ""
-	entries, err := os.ReadDir(extractPath)
+	// 确定模板来源
+	source, err := NewTemplateSource(meta)
 	if err != nil {
 		return err
 	}
+	fmt.Printf("  🌐 正在获取模板（%s）...\n", source)
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			templateDir = filepath.Join(extractPath, entry.Name())
-			break
-		}
-	}
-
-	if templateDir == "" {
-		return fmt.Errorf("无法找到模板目录")
+	templateDir, cleanup, err := source.Fetch()
+	defer cleanup()
+	if err != nil {
+		return fmt.Errorf("获取模板失败: %w", err)
 	}
 
-	// 复制并处理模板
-	return copyDir(templateDir, dst, meta)
-}
+	// 中断时删除临时文件并撤销已写入的文件
+	gen := &generation{dst: meta.Name}
+	stop := onInterrupt(func() {
+		gen.rollback()
+		cleanup()
+	})
+	defer stop()
 
-// downloadFile 下载文件
-func downloadFile(url, filepath string) error {
-	resp, err := http.Get(url)
+	// 计算每个文件的目标路径与内容
+	ops, err := planDir(templateDir, meta)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
+	if merge {
+		markConflicts(ops, meta.Name, meta.Force)
+	}
+	if meta.Features != nil {
+		fmt.Printf("  ✓ 启用功能：%s\n", strings.Join(meta.Features, ", "))
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("下载失败: 状态码 %d", resp.StatusCode)
+	if meta.DryRun {
+		fmt.Printf("\n📋 生成计划（%s/，不会写入任何文件）：\n", meta.Name)
+		PrintPlan(os.Stdout, ops, meta.Hooks)
+		return nil
 	}
 
-	out, err := os.Create(filepath)
-	if err != nil {
+	// 先写入临时目录，成功后再移动到目标位置
+	if err := gen.run(ops); err != nil {
+		gen.rollback()
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	fmt.Println("  ✓ 项目文件生成完成")
+	return nil
 }
 
 // unzip 解压ZIP文件
@@ -190,6 +225,13 @@ func unzip(src, dest string) error {
 			return err
 		}
 
+		if f.Mode()&os.ModeSymlink != 0 {
+			if err := unzipSymlink(f, fpath, dest); err != nil {
+				return err
+			}
+			continue
+		}
+
 		outFile, err := os.OpenFile(fpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
 		if err != nil {
 			return err
@@ -211,77 +253,33 @@ func unzip(src, dest string) error {
 	return nil
 }
 
-// copyDir 复制目录并替换占位符
-func copyDir(src, dst string, meta *ProjectMeta) error {
-	// 创建目标目录
-	if err := os.MkdirAll(dst, 0755); err != nil {
+// unzipSymlink 还原归档中的符号链接，链接只能指向解压目录之内
+func unzipSymlink(f *zip.File, fpath, dest string) error {
+	rc, err := f.Open()
+	if err != nil {
 		return err
 	}
-
-	// 遍历源目录
-	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// 跳过 CLI 目录、临时目录、构建产物和其他无关目录
-		relPath, _ := filepath.Rel(src, path)
-		if shouldSkip(relPath) {
-			if info.IsDir() {
-				return filepath.SkipDir
-			}
-			return nil
-		}
-
-		// 计算目标路径
-		dstPath := filepath.Join(dst, relPath)
-
-		if info.IsDir() {
-			return os.MkdirAll(dstPath, info.Mode())
-		}
-
-		// 复制文件并替换内容
-		return copyFileWithReplace(path, dstPath, meta)
-	})
-}
-
-// shouldSkip 判断是否跳过文件/目录
-func shouldSkip(path string) bool {
-	skipDirs := []string{
-		"cli",
-		".git",
-		"backend/tmp",
-		"backend/bin",
-		"frontend/node_modules",
-		"frontend/dist",
-		"requirements",
-		".github",
-		".vscode",
-		".idea",
+	target, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
 	}
 
-	for _, skip := range skipDirs {
-		if strings.HasPrefix(path, skip) {
-			return true
-		}
+	link := filepath.FromSlash(string(target))
+	resolved := filepath.Join(filepath.Dir(fpath), link)
+	if filepath.IsAbs(link) || !strings.HasPrefix(resolved, filepath.Clean(dest)+string(os.PathSeparator)) {
+		return fmt.Errorf("非法符号链接: %s -> %s", f.Name, target)
 	}
-
-	return false
+	return os.Symlink(link, fpath)
 }
 
-// copyFileWithReplace 复制文件并替换占位符
-func copyFileWithReplace(src, dst string, meta *ProjectMeta) error {
-	// 读取源文件
-	content, err := os.ReadFile(src)
+// copyDir 复制目录，模板包含 skeleton.yaml 时按清单渲染，否则替换占位符
+func copyDir(src, dst string, meta *ProjectMeta) error {
+	ops, err := planDir(src, meta)
 	if err != nil {
 		return err
 	}
-
-	// 替换占位符
-	newContent := replaceContent(string(content), meta)
-
-	// 写入目标文件
-	return os.WriteFile(dst, []byte(newContent), 0644)
+	return writePlan(ops, dst)
 }
 
 // replaceContent 替换文件内容中的占位符
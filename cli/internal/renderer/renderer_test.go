@@ -0,0 +1,106 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"my_project", false},
+		{"Shop2", false},
+		{"", true},
+		{"my-project", true},
+		{"2shop", true},
+		{"_shop", true},
+		{"my project", true},
+		{"../shop", true},
+		{"func", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateModule(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"github.com/user/my_project", false},
+		{"example.com/shop/v2", false},
+		{"shop", false},
+		{"", true},
+		{"/shop", true},
+		{"github.com//shop", true},
+		{"github.com/user/", true},
+		{"github.com/user/my project", true},
+		{"github.com/user/shop.", true},
+		{".hidden/shop", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateModule(tt.path); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateModule(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPromptProjectInfo_NonInteractive(t *testing.T) {
+	meta := &ProjectMeta{Name: "shop", NonInteractive: true}
+	if err := PromptProjectInfo(meta); err != nil {
+		t.Fatal(err)
+	}
+	if meta.Version != "1.0.0" || meta.Module != "github.com/user/shop" {
+		t.Errorf("默认值错误: version=%s module=%s", meta.Version, meta.Module)
+	}
+
+	tests := []struct {
+		name string
+		meta *ProjectMeta
+	}{
+		{"缺少项目名称", &ProjectMeta{NonInteractive: true}},
+		{"项目名称无效", &ProjectMeta{Name: "my-shop", NonInteractive: true}},
+		{"模块路径无效", &ProjectMeta{Name: "shop", Module: "example.com/my shop", NonInteractive: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := PromptProjectInfo(tt.meta); err == nil {
+				t.Error("应返回错误")
+			}
+		})
+	}
+}
+
+func TestManifest_NonInteractive(t *testing.T) {
+	m := &Manifest{
+		Variables: []Variable{{Name: "Author", Required: true}},
+		Features: []Feature{
+			{Name: "mcp", Default: true},
+			{Name: "auth", Default: true, Depends: []string{"mcp"}},
+			{Name: "docker"},
+		},
+	}
+
+	meta := &ProjectMeta{Name: "shop", NonInteractive: true}
+	if err := m.ResolveFeatures(meta); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"mcp", "auth"}; !reflect.DeepEqual(meta.Features, want) {
+		t.Errorf("Features = %v, want %v", meta.Features, want)
+	}
+
+	if err := m.ResolveVariables(meta); err == nil {
+		t.Error("缺少必填变量时应返回错误")
+	}
+	meta.Vars = map[string]string{"Author": "alice"}
+	if err := m.ResolveVariables(meta); err != nil {
+		t.Errorf("ResolveVariables() error = %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package renderer
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// snapshotFS 内嵌的默认模板快照（由 make cli-snapshot 生成 snapshot/template.zip）
+//
+//go:embed snapshot
+var snapshotFS embed.FS
+
+// snapshotPath 内嵌快照在 snapshotFS 中的路径
+const snapshotPath = "snapshot/template.zip"
+
+// TemplateSource 模板来源
+type TemplateSource interface {
+	// Fetch 准备模板目录，返回模板根目录与清理函数（清理函数总是非空）
+	Fetch() (string, func(), error)
+	// String 来源描述，用于输出
+	String() string
+}
+
+// NewTemplateSource 根据项目元信息选择模板来源：本地目录 > 本地 ZIP > 内嵌快照 > 远程地址
+func NewTemplateSource(meta *ProjectMeta) (TemplateSource, error) {
+	count := 0
+	for _, set := range []bool{meta.TemplateDir != "", meta.TemplateZip != "", meta.Offline, meta.TemplateURL != ""} {
+		if set {
+			count++
+		}
+	}
+	if count > 1 {
+		return nil, fmt.Errorf("--template-url、--template-dir、--template-zip、--offline 只能指定一个")
+	}
+
+	if meta.TemplateRef != "" && (meta.TemplateDir != "" || meta.TemplateZip != "" || meta.Offline) {
+		return nil, fmt.Errorf("--template-ref 仅适用于远程模板")
+	}
+
+	switch {
+	case meta.TemplateDir != "":
+		return DirSource{Dir: meta.TemplateDir}, nil
+	case meta.TemplateZip != "":
+		return ZipSource{Path: meta.TemplateZip, SHA256: meta.TemplateSHA256}, nil
+	case meta.Offline:
+		return EmbeddedSource{}, nil
+	}
+
+	source := RemoteSource{URL: meta.TemplateURL, Ref: meta.TemplateRef, SHA256: meta.TemplateSHA256, Options: meta.Download}
+	if source.URL == "" {
+		source.URL = DefaultTemplateURL
+	}
+	var err error
+	if source.URL, source.Ref, err = ExpandTemplateURL(source.URL, source.Ref); err != nil {
+		return nil, err
+	}
+	if _, err := ResolveTemplateURL(source.URL, source.Ref); err != nil {
+		return nil, err
+	}
+	if source.Options.Progress == nil && isTerminal(os.Stdout) {
+		source.Options.Progress = os.Stdout
+	}
+	if !meta.NoCache {
+		// 缓存目录不可用时直接下载
+		if source.Cache, _ = DefaultTemplateCache(); source.Cache != nil {
+			source.Cache.Options = source.Options
+		}
+	}
+	return source, nil
+}
+
+// RemoteSource 从远程地址下载 ZIP 模板
+type RemoteSource struct {
+	URL     string
+	Ref     string          // 模板版本（tag、分支或 commit）
+	SHA256  string          // 期望的归档 SHA-256，为空不校验
+	Cache   *TemplateCache  // 为空时不缓存
+	Options DownloadOptions // 下载选项（认证、代理、重试等）
+}
+
+// Fetch 下载（或从缓存读取）并解压模板
+func (s RemoteSource) Fetch() (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "ai_skeleton_template_*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	zipPath := filepath.Join(tempDir, "template.zip")
+	if s.Cache != nil {
+		entry, err := s.Cache.Fetch(s.URL, s.Ref, s.SHA256)
+		if err != nil {
+			return "", cleanup, err
+		}
+		zipPath = entry.ArchivePath()
+	} else {
+		url, err := ResolveTemplateURL(s.URL, s.Ref)
+		if err != nil {
+			return "", cleanup, err
+		}
+		d, err := newDownloader(s.Options)
+		if err != nil {
+			return "", cleanup, err
+		}
+		if _, err := d.download(url, zipPath, ""); err != nil {
+			return "", cleanup, err
+		}
+		if err := verifyFileChecksum(zipPath, s.SHA256); err != nil {
+			return "", cleanup, err
+		}
+	}
+
+	dir, err := extractTemplate(zipPath, filepath.Join(tempDir, "extracted"))
+	return dir, cleanup, err
+}
+
+func (s RemoteSource) String() string {
+	name := "远程仓库 " + s.URL
+	if s.URL == DefaultTemplateURL {
+		name = "官方仓库"
+	}
+	if s.Ref != "" {
+		name += "（" + s.Ref + "）"
+	}
+	return name
+}
+
+// DirSource 本地模板目录（如脚手架仓库的克隆）
+type DirSource struct {
+	Dir string
+}
+
+// Fetch 直接使用本地目录，无需清理
+func (s DirSource) Fetch() (string, func(), error) {
+	info, err := os.Stat(s.Dir)
+	if err != nil {
+		return "", func() {}, fmt.Errorf("模板目录不可用: %w", err)
+	}
+	if !info.IsDir() {
+		return "", func() {}, fmt.Errorf("%s 不是目录", s.Dir)
+	}
+	dir, err := filepath.Abs(s.Dir)
+	return dir, func() {}, err
+}
+
+func (s DirSource) String() string {
+	return "本地目录 " + s.Dir
+}
+
+// ZipSource 本地 ZIP 模板（如 GitHub 下载的仓库归档）
+type ZipSource struct {
+	Path   string
+	SHA256 string // 期望的归档 SHA-256，为空不校验
+}
+
+// Fetch 解压本地 ZIP
+func (s ZipSource) Fetch() (string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "ai_skeleton_template_*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	if err := verifyFileChecksum(s.Path, s.SHA256); err != nil {
+		return "", cleanup, err
+	}
+	dir, err := extractTemplate(s.Path, tempDir)
+	return dir, cleanup, err
+}
+
+func (s ZipSource) String() string {
+	return "本地文件 " + s.Path
+}
+
+// EmbeddedSource 编译进 CLI 的模板快照，适用于无法访问外网的环境
+type EmbeddedSource struct{}
+
+// Fetch 将内嵌快照写入临时目录并解压
+func (s EmbeddedSource) Fetch() (string, func(), error) {
+	data, err := snapshotFS.ReadFile(snapshotPath)
+	if err != nil {
+		return "", func() {}, fmt.Errorf("当前 CLI 未内嵌模板快照，请在脚手架仓库执行 make cli-snapshot 后重新构建 CLI，或使用 --template-dir/--template-zip")
+	}
+
+	tempDir, err := os.MkdirTemp("", "ai_skeleton_template_*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	zipPath := filepath.Join(tempDir, "template.zip")
+	if err := os.WriteFile(zipPath, data, 0644); err != nil {
+		return "", cleanup, err
+	}
+	dir, err := extractTemplate(zipPath, filepath.Join(tempDir, "extracted"))
+	return dir, cleanup, err
+}
+
+func (s EmbeddedSource) String() string {
+	return "内嵌快照"
+}
+
+// extractTemplate 解压模板 ZIP 并返回模板根目录
+func extractTemplate(zipPath, dest string) (string, error) {
+	if err := unzip(zipPath, dest); err != nil {
+		return "", err
+	}
+	return templateRoot(dest)
+}
+
+// templateRoot 归档只包含一个顶层目录时（如 ai_skeleton-main）以其为模板根目录，否则使用解压目录
+func templateRoot(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("无法找到模板目录")
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
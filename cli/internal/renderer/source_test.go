@@ -0,0 +1,161 @@
+package renderer
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeZip 按 文件名 -> 内容 创建 ZIP
+func writeZip(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "template.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewTemplateSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    ProjectMeta
+		want    TemplateSource
+		wantErr bool
+	}{
+		{name: "默认官方仓库", meta: ProjectMeta{NoCache: true}, want: RemoteSource{URL: DefaultTemplateURL}},
+		{name: "自定义地址", meta: ProjectMeta{TemplateURL: "https://x/a.zip", NoCache: true}, want: RemoteSource{URL: "https://x/a.zip"}},
+		{name: "固定版本", meta: ProjectMeta{TemplateRef: "v1.2.0", NoCache: true}, want: RemoteSource{URL: DefaultTemplateURL, Ref: "v1.2.0"}},
+		{name: "仓库简写", meta: ProjectMeta{TemplateURL: "gh:org/repo@v1", NoCache: true}, want: RemoteSource{URL: "https://api.github.com/repos/org/repo/zipball/{ref}", Ref: "v1"}},
+		{name: "下载选项", meta: ProjectMeta{TemplateURL: "gl:org/repo", Download: DownloadOptions{Token: "t", Retries: 3}, NoCache: true}, want: RemoteSource{URL: "https://gitlab.com/api/v4/projects/org%2Frepo/repository/archive.zip", Options: DownloadOptions{Token: "t", Retries: 3}}},
+		{name: "简写与 --template-ref 冲突", meta: ProjectMeta{TemplateURL: "gh:org/repo@v1", TemplateRef: "v2"}, wantErr: true},
+		{name: "本地目录", meta: ProjectMeta{TemplateDir: "./tpl"}, want: DirSource{Dir: "./tpl"}},
+		{name: "本地 ZIP", meta: ProjectMeta{TemplateZip: "a.zip", TemplateSHA256: "abc"}, want: ZipSource{Path: "a.zip", SHA256: "abc"}},
+		{name: "内嵌快照", meta: ProjectMeta{Offline: true}, want: EmbeddedSource{}},
+		{name: "多个来源冲突", meta: ProjectMeta{Offline: true, TemplateDir: "./tpl"}, wantErr: true},
+		{name: "本地模板不支持版本", meta: ProjectMeta{TemplateDir: "./tpl", TemplateRef: "v1"}, wantErr: true},
+		{name: "地址无法指定版本", meta: ProjectMeta{TemplateURL: "https://x/a.zip", TemplateRef: "v1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewTemplateSource(&tt.meta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZipSource_Fetch(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string // 模板根目录下应存在的文件
+	}{
+		{
+			name:  "GitHub 归档（单个顶层目录）",
+			files: map[string]string{"ai_skeleton-main/Makefile": "all:", "ai_skeleton-main/backend/go.mod": "module x"},
+			want:  "backend/go.mod",
+		},
+		{
+			name:  "平铺的模板",
+			files: map[string]string{"Makefile": "all:", "backend/go.mod": "module x"},
+			want:  "backend/go.mod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, cleanup, err := ZipSource{Path: writeZip(t, tt.files)}.Fetch()
+			defer cleanup()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.want)); err != nil {
+				t.Errorf("模板根目录 %s 中缺少 %s", dir, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnzip_ModesAndSymlinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		link    string
+		wantErr bool
+	}{
+		{name: "模板内的链接", link: "install.sh"},
+		{name: "指向解压目录之外", link: "../../etc/passwd", wantErr: true},
+		{name: "绝对路径", link: "/etc/passwd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.zip")
+			f, _ := os.Create(path)
+			w := zip.NewWriter(f)
+			for _, e := range []struct {
+				name, content string
+				mode          os.FileMode
+			}{
+				{"tpl/install.sh", "#!/bin/sh\n", 0755},
+				{"tpl/setup.sh", tt.link, os.ModeSymlink | 0777},
+			} {
+				h := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+				h.SetMode(e.mode)
+				fw, _ := w.CreateHeader(h)
+				fw.Write([]byte(e.content))
+			}
+			w.Close()
+			f.Close()
+
+			dest := t.TempDir()
+			err := unzip(path, dest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unzip() err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if info, err := os.Stat(filepath.Join(dest, "tpl", "install.sh")); err != nil || info.Mode().Perm()&0100 == 0 {
+				t.Errorf("install.sh 应保留可执行权限")
+			}
+			if link, err := os.Readlink(filepath.Join(dest, "tpl", "setup.sh")); err != nil || link != tt.link {
+				t.Errorf("setup.sh = %q, %v", link, err)
+			}
+		})
+	}
+}
+
+func TestDirSource_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	got, cleanup, err := DirSource{Dir: dir}.Fetch()
+	defer cleanup()
+	if err != nil || got != dir {
+		t.Fatalf("Fetch() = %s, %v", got, err)
+	}
+
+	_, cleanup, err = DirSource{Dir: filepath.Join(dir, "missing")}.Fetch()
+	defer cleanup()
+	if err == nil {
+		t.Fatal("目录不存在时应返回错误")
+	}
+}